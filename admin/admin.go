@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+	"myapp/notify"
+	"myapp/workflow"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid input")
+	ErrNotFound = errors.New("not found")
+	ErrExists   = errors.New("already exists")
+)
+
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,50}$`)
+
+// Service - операции обслуживания, для которых нет эндпоинтов API: учётные записи сотрудников,
+// организации и их ответственные лица, принудительное закрытие и выгрузка тендеров.
+// Проверяет входные данные так же строго, как API, чтобы не приходилось править таблицы вручную
+type Service struct {
+	DB *gorm.DB
+	// Notifications необязательны: без них закрытие тендера не рассылает уведомления
+	Notifications *notify.Service
+}
+
+type EmployeeInput struct {
+	// ID задаётся, когда идентификатор должен быть заранее известен, например в фикстурах; иначе создаётся новый
+	ID        uuid.UUID
+	Username  string
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+func (s Service) CreateEmployee(ctx context.Context, in EmployeeInput) (models.Employee, error) {
+	db := s.DB.WithContext(ctx)
+
+	if !validUsername.MatchString(in.Username) {
+		return models.Employee{}, fmt.Errorf("%w: username must be 1-50 letters, digits, '_', '.' or '-'", ErrInvalid)
+	}
+	if len(in.FirstName) > 50 || len(in.LastName) > 50 {
+		return models.Employee{}, fmt.Errorf("%w: first and last name must be at most 50 characters", ErrInvalid)
+	}
+	if in.Email != "" {
+		if _, err := mail.ParseAddress(in.Email); err != nil {
+			return models.Employee{}, fmt.Errorf("%w: invalid email %q", ErrInvalid, in.Email)
+		}
+	}
+
+	var count int64
+	if err := db.Model(&models.Employee{}).Where("username = ?", in.Username).Count(&count).Error; err != nil {
+		return models.Employee{}, err
+	}
+	if count > 0 {
+		return models.Employee{}, fmt.Errorf("%w: employee %q", ErrExists, in.Username)
+	}
+
+	employee := models.Employee{
+		ID:        newID(in.ID),
+		Username:  in.Username,
+		FirstName: in.FirstName,
+		LastName:  in.LastName,
+		Email:     in.Email,
+	}
+	if err := db.Omit("Organization").Create(&employee).Error; err != nil {
+		return models.Employee{}, err
+	}
+
+	return employee, nil
+}
+
+type OrganizationInput struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Type        models.OrganizationType
+}
+
+func (s Service) CreateOrganization(ctx context.Context, in OrganizationInput) (models.Organization, error) {
+	db := s.DB.WithContext(ctx)
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || len(in.Name) > 100 {
+		return models.Organization{}, fmt.Errorf("%w: name must be 1-100 characters", ErrInvalid)
+	}
+	switch in.Type {
+	case models.IE, models.LLC, models.JSC:
+	default:
+		return models.Organization{}, fmt.Errorf("%w: type must be IE, LLC or JSC", ErrInvalid)
+	}
+
+	// Имя не уникально в схеме, но организации ищутся по нему в командах, поэтому дубликаты не допускаются
+	var count int64
+	if err := db.Model(&models.Organization{}).Where("name = ?", in.Name).Count(&count).Error; err != nil {
+		return models.Organization{}, err
+	}
+	if count > 0 {
+		return models.Organization{}, fmt.Errorf("%w: organization %q", ErrExists, in.Name)
+	}
+
+	org := models.Organization{
+		ID:          newID(in.ID),
+		Name:        in.Name,
+		Description: in.Description,
+		Type:        in.Type,
+	}
+	if err := db.Omit("Employees").Create(&org).Error; err != nil {
+		return models.Organization{}, err
+	}
+
+	return org, nil
+}
+
+// FindOrganization ищет организацию по идентификатору или по имени
+func (s Service) FindOrganization(ctx context.Context, ref string) (models.Organization, error) {
+	db := s.DB.WithContext(ctx)
+
+	var org models.Organization
+	query := db.Where("name = ?", ref)
+	if id, err := uuid.Parse(ref); err == nil {
+		query = db.Where("id = ?", id)
+	}
+
+	if err := query.First(&org).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return org, fmt.Errorf("%w: organization %q", ErrNotFound, ref)
+		}
+		return org, err
+	}
+
+	return org, nil
+}
+
+func (s Service) FindEmployee(ctx context.Context, username string) (models.Employee, error) {
+	var employee models.Employee
+	if err := s.DB.WithContext(ctx).Where("username = ?", username).First(&employee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return employee, fmt.Errorf("%w: employee %q", ErrNotFound, username)
+		}
+		return employee, err
+	}
+
+	return employee, nil
+}
+
+// AddResponsible назначает сотрудника ответственным лицом организации
+func (s Service) AddResponsible(ctx context.Context, orgRef, username string) (models.OrganizationResponsible, error) {
+	org, err := s.FindOrganization(ctx, orgRef)
+	if err != nil {
+		return models.OrganizationResponsible{}, err
+	}
+
+	employee, err := s.FindEmployee(ctx, username)
+	if err != nil {
+		return models.OrganizationResponsible{}, err
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	var count int64
+	if err := db.Model(&models.OrganizationResponsible{}).Where("organization_id = ? AND user_id = ?", org.ID, employee.ID).Count(&count).Error; err != nil {
+		return models.OrganizationResponsible{}, err
+	}
+	if count > 0 {
+		return models.OrganizationResponsible{}, fmt.Errorf("%w: %q is already responsible for %q", ErrExists, username, org.Name)
+	}
+
+	resp := models.OrganizationResponsible{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		UserID:         employee.ID,
+	}
+	if err := db.Omit("Organization", "User").Create(&resp).Error; err != nil {
+		return models.OrganizationResponsible{}, err
+	}
+
+	return resp, nil
+}
+
+// CloseTender закрывает опубликованный тендер, например зависший после ухода всех ответственных лиц.
+// Используется тот же переход, что и при закрытии через API: триггер сохраняет версию в истории,
+// а участники получают событие и уведомление
+func (s Service) CloseTender(ctx context.Context, tenderID string) (models.Tender, error) {
+	tender, err := s.findTender(ctx, tenderID)
+	if err != nil {
+		return tender, err
+	}
+
+	if err := workflow.CloseTender(s.DB.WithContext(ctx), s.Notifications, &tender); err != nil {
+		if errors.Is(err, workflow.ErrNotPublished) {
+			return tender, fmt.Errorf("%w: tender is %s, only published tenders can be closed", ErrInvalid, tender.Status)
+		}
+		return tender, err
+	}
+
+	return tender, nil
+}
+
+func (s Service) findTender(ctx context.Context, tenderID string) (models.Tender, error) {
+	var tender models.Tender
+
+	id, err := uuid.Parse(tenderID)
+	if err != nil {
+		return tender, fmt.Errorf("%w: invalid tender id %q", ErrInvalid, tenderID)
+	}
+
+	if err := s.DB.WithContext(ctx).Where("id = ?", id).First(&tender).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tender, fmt.Errorf("%w: tender %s", ErrNotFound, tenderID)
+		}
+		return tender, err
+	}
+
+	return tender, nil
+}
+
+func newID(id uuid.UUID) uuid.UUID {
+	if id == uuid.Nil {
+		return uuid.New()
+	}
+	return id
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+
+	"myapp/models"
+)
+
+// TenderExport - тендер со всеми связанными данными для передачи в аудит или другую систему
+type TenderExport struct {
+	Tender       models.Tender           `json:"tender"`
+	Organization models.Organization     `json:"organization"`
+	History      []models.TenderHistory  `json:"history"`
+	Bids         []models.Bid            `json:"bids"`
+	BidHistory   []models.BidHistory     `json:"bidHistory"`
+	Decisions    []models.Decision       `json:"decisions"`
+	Reviews      []models.Review         `json:"reviews"`
+	Questions    []models.TenderQuestion `json:"questions"`
+	Contracts    []models.Contract       `json:"contracts"`
+}
+
+// ExportTender собирает тендер, его историю, предложения, решения, отзывы, вопросы и договоры
+func (s Service) ExportTender(ctx context.Context, tenderID string) (TenderExport, error) {
+	var export TenderExport
+
+	tender, err := s.findTender(ctx, tenderID)
+	if err != nil {
+		return export, err
+	}
+
+	db := s.DB.WithContext(ctx)
+	bidIDs := db.Model(&models.Bid{}).Select("id").Where("tender_id = ?", tender.ID)
+
+	steps := []func() error{
+		func() error { return db.Preload("Lots").Where("id = ?", tender.ID).First(&export.Tender).Error },
+		func() error { return db.Where("id = ?", tender.OrganizationID).First(&export.Organization).Error },
+		func() error { return db.Order("version").Where("tender_id = ?", tender.ID).Find(&export.History).Error },
+		func() error {
+			return db.Preload("Lots").Order("created_at").Where("tender_id = ?", tender.ID).Find(&export.Bids).Error
+		},
+		func() error {
+			return db.Order("bid_id, version").Where("bid_id IN (?)", bidIDs).Find(&export.BidHistory).Error
+		},
+		func() error {
+			return db.Order("created_at").Where("bid_id IN (?)", bidIDs).Find(&export.Decisions).Error
+		},
+		func() error {
+			return db.Preload("Reply").Order("created_at").Where("bid_id IN (?)", bidIDs).Find(&export.Reviews).Error
+		},
+		func() error {
+			return db.Order("created_at").Where("tender_id = ?", tender.ID).Find(&export.Questions).Error
+		},
+		func() error {
+			return db.Order("created_at").Where("tender_id = ?", tender.ID).Find(&export.Contracts).Error
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return export, err
+		}
+	}
+
+	return export, nil
+}
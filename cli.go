@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"myapp/admin"
+	"myapp/config"
+	"myapp/database"
+	"myapp/health"
+	"myapp/models"
+	"myapp/notify"
+	"myapp/openapi"
+	"myapp/router"
+	"myapp/seed"
+)
+
+func runMigrate(cfg *config.Config, _ []string) error {
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	return database.Migrate(db)
+}
+
+func runConfigPrint(cfg *config.Config, _ []string) error {
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// runOpenAPIPrint выводит спецификацию API, например для генерации клиентов
+func runOpenAPIPrint(_ *config.Config, _ []string) error {
+	data, err := openapi.JSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// runOpenAPICheck проверяет, что каждый маршрут сервера описан в спецификации и каждая операция спецификации
+// обслуживается маршрутом. Маршруты регистрируются без подключения к базе данных, поэтому проверку можно запускать в CI
+func runOpenAPICheck(cfg *config.Config, _ []string) error {
+	gin.SetMode(cfg.HTTP.GinMode)
+	r := router.SetupRouter(cfg, nil, nil, nil, nil, nil, health.NewChecker(time.Second))
+	routes := router.Routes(r)
+	if err := openapi.Check(routes); err != nil {
+		return err
+	}
+
+	fmt.Printf("All %d routes are documented\n", len(routes))
+	return nil
+}
+
+func runUserCreate(cfg *config.Config, args []string) error {
+	var in admin.EmployeeInput
+
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	fs.StringVar(&in.Username, "username", "", "unique username (required)")
+	fs.StringVar(&in.FirstName, "first-name", "", "first name")
+	fs.StringVar(&in.LastName, "last-name", "", "last name")
+	fs.StringVar(&in.Email, "email", "", "email for notifications")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	svc, err := adminService(cfg)
+	if err != nil {
+		return err
+	}
+
+	employee, err := svc.CreateEmployee(context.Background(), in)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(os.Stdout, employee)
+}
+
+func runOrgCreate(cfg *config.Config, args []string) error {
+	var in admin.OrganizationInput
+	var orgType string
+
+	fs := flag.NewFlagSet("org create", flag.ContinueOnError)
+	fs.StringVar(&in.Name, "name", "", "unique organization name (required)")
+	fs.StringVar(&in.Description, "description", "", "description")
+	fs.StringVar(&orgType, "type", "", "organization type: IE, LLC or JSC (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	in.Type = models.OrganizationType(orgType)
+
+	svc, err := adminService(cfg)
+	if err != nil {
+		return err
+	}
+
+	org, err := svc.CreateOrganization(context.Background(), in)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(os.Stdout, org)
+}
+
+func runOrgAddResponsible(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("org add-responsible", flag.ContinueOnError)
+	org := fs.String("org", "", "organization id or name (required)")
+	username := fs.String("username", "", "employee username (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *org == "" || *username == "" {
+		return fmt.Errorf("%w: -org and -username are required", admin.ErrInvalid)
+	}
+
+	svc, err := adminService(cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.AddResponsible(context.Background(), *org, *username)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(os.Stdout, map[string]any{"organizationId": resp.OrganizationID, "userId": resp.UserID})
+}
+
+func runTenderClose(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("tender close", flag.ContinueOnError)
+	id := fs.String("id", "", "tender id (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	svc, err := adminService(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Уведомления о закрытии отправляются так же, как при закрытии через API
+	svc.Notifications = notify.NewService(newNotifier(cfg), 100)
+	svc.Notifications.Start(1)
+	defer svc.Notifications.Stop(context.Background())
+
+	tender, err := svc.CloseTender(context.Background(), *id)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(os.Stdout, tender)
+}
+
+func runTenderExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("tender export", flag.ContinueOnError)
+	id := fs.String("id", "", "tender id (required)")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	svc, err := adminService(cfg)
+	if err != nil {
+		return err
+	}
+
+	export, err := svc.ExportTender(context.Background(), *id)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return printJSON(os.Stdout, export)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(f, export); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runSeed загружает фикстуры из файлов -file (YAML или JSON) или встроенные демонстрационные данные.
+// Повторный запуск пропускает уже существующие записи
+func runSeed(cfg *config.Config, args []string) error {
+	var files stringList
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Var(&files, "file", "fixture file (.yaml, .yml or .json), can be repeated; demo data if omitted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var fixtures []seed.Fixture
+	if len(files) == 0 {
+		f, err := seed.Demo()
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, f)
+	}
+	for _, path := range files {
+		f, err := seed.ParseFile(path)
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fixtures {
+		result, err := seed.Load(context.Background(), db, f, cfg.Quorum)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+	}
+	return nil
+}
+
+// stringList - флаг, который можно указать несколько раз
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func adminService(cfg *config.Config) (admin.Service, error) {
+	db, err := connect(cfg)
+	if err != nil {
+		return admin.Service{}, err
+	}
+
+	return admin.Service{DB: db}, nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
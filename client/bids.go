@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"myapp/models"
+)
+
+// BidService - операции с предложениями
+type BidService struct {
+	client *Client
+}
+
+type CreateBidInput struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	TenderID    uuid.UUID         `json:"tenderId"`
+	AuthorType  models.AuthorType `json:"authorType"`
+	AuthorID    uuid.UUID         `json:"authorId"`
+	// OrganizationID обязательна для предложения от организации, если автор - ответственное лицо нескольких организаций
+	OrganizationID *uuid.UUID    `json:"organizationId,omitempty"`
+	Price          *float64      `json:"price,omitempty"`
+	Lots           []BidLotInput `json:"lots,omitempty"`
+}
+
+type BidLotInput struct {
+	LotID uuid.UUID `json:"lotId"`
+	Price float64   `json:"price"`
+}
+
+// EditBidInput - изменяемые поля предложения; пустые поля не меняются
+type EditBidInput struct {
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Status      models.BidStatus `json:"status,omitempty"`
+}
+
+// TenderBids - предложения по тендеру. Для закрытого тендера до вскрытия сервер сообщает
+// только их число: в этом случае Bids пуст, а Sealed заполнен
+type TenderBids struct {
+	Bids   []models.Bid
+	Sealed *SealedBids
+}
+
+type SealedBids struct {
+	Count    int64      `json:"count"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+func bidPath(id uuid.UUID, action string) string {
+	return "/api/bids/" + id.String() + action
+}
+
+func (s *BidService) Create(ctx context.Context, in CreateBidInput) (models.Bid, error) {
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPost, path: "/api/bids/new", body: in}, &bid)
+	return bid, err
+}
+
+// Mine возвращает страницу предложений пользователя
+func (s *BidService) Mine(ctx context.Context, page Page) ([]models.Bid, error) {
+	var bids []models.Bid
+	err := s.client.do(ctx, request{method: http.MethodGet, path: "/api/bids/my", query: page.values()}, &bids)
+	return bids, err
+}
+
+// AllMine перебирает все предложения пользователя
+func (s *BidService) AllMine(ctx context.Context) iter.Seq2[models.Bid, error] {
+	return all(ctx, Page{}, s.Mine)
+}
+
+// ForTender возвращает страницу предложений по тендеру tenderID
+func (s *BidService) ForTender(ctx context.Context, tenderID uuid.UUID, page Page) (TenderBids, error) {
+	var raw json.RawMessage
+	if err := s.client.do(ctx, request{method: http.MethodGet, path: bidPath(tenderID, "/list"), query: page.values()}, &raw); err != nil {
+		return TenderBids{}, err
+	}
+
+	// Список приходит массивом, сведения о закрытом тендере - объектом
+	if len(raw) > 0 && raw[0] == '{' {
+		var sealed SealedBids
+		if err := json.Unmarshal(raw, &sealed); err != nil {
+			return TenderBids{}, err
+		}
+		return TenderBids{Sealed: &sealed}, nil
+	}
+
+	var result TenderBids
+	if err := json.Unmarshal(raw, &result.Bids); err != nil {
+		return TenderBids{}, err
+	}
+	return result, nil
+}
+
+// AllForTender перебирает все предложения по тендеру. Для закрытого тендера до вскрытия
+// перебор пуст: число предложений возвращает ForTender
+func (s *BidService) AllForTender(ctx context.Context, tenderID uuid.UUID) iter.Seq2[models.Bid, error] {
+	return all(ctx, Page{}, func(ctx context.Context, p Page) ([]models.Bid, error) {
+		result, err := s.ForTender(ctx, tenderID, p)
+		return result.Bids, err
+	})
+}
+
+func (s *BidService) Status(ctx context.Context, id uuid.UUID) (models.BidStatus, error) {
+	var status models.BidStatus
+	err := s.client.do(ctx, request{method: http.MethodGet, path: bidPath(id, "/status")}, &status)
+	return status, err
+}
+
+func (s *BidService) SetStatus(ctx context.Context, id uuid.UUID, status models.BidStatus) (models.Bid, error) {
+	var bid models.Bid
+	query := url.Values{"status": {string(status)}}
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(id, "/status"), query: query}, &bid)
+	return bid, err
+}
+
+func (s *BidService) Edit(ctx context.Context, id uuid.UUID, in EditBidInput) (models.Bid, error) {
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPatch, path: bidPath(id, "/edit"), body: in}, &bid)
+	return bid, err
+}
+
+// Rollback создаёт новую версию предложения с параметрами версии version
+func (s *BidService) Rollback(ctx context.Context, id uuid.UUID, version int) (models.Bid, error) {
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(id, "/rollback/"+strconv.Itoa(version))}, &bid)
+	return bid, err
+}
+
+// LowerPrice снижает цену предложения в открытом раунде аукциона
+func (s *BidService) LowerPrice(ctx context.Context, id uuid.UUID, price float64) (models.Bid, error) {
+	var bid models.Bid
+	query := url.Values{"price": {strconv.FormatFloat(price, 'f', -1, 64)}}
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(id, "/price"), query: query}, &bid)
+	return bid, err
+}
+
+func (s *BidService) Withdraw(ctx context.Context, id uuid.UUID, reason string) (models.BidWithdrawal, error) {
+	var withdrawal models.BidWithdrawal
+	body := map[string]string{"reason": reason}
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(id, "/withdraw"), body: body}, &withdrawal)
+	return withdrawal, err
+}
+
+// Resubmit возвращает отозванное предложение до окончания срока подачи
+func (s *BidService) Resubmit(ctx context.Context, id uuid.UUID) (models.Bid, error) {
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(id, "/resubmit")}, &bid)
+	return bid, err
+}
+
+func (s *BidService) Withdrawals(ctx context.Context, id uuid.UUID) ([]models.BidWithdrawal, error) {
+	var withdrawals []models.BidWithdrawal
+	err := s.client.do(ctx, request{method: http.MethodGet, path: bidPath(id, "/withdrawals")}, &withdrawals)
+	return withdrawals, err
+}
@@ -0,0 +1,263 @@
+// Package client - типизированный клиент API тендеров для сервисов на Go.
+// Ответы возвращаются в виде структур models, ошибки API - в виде *Error
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 200 * time.Millisecond
+	defaultTimeout = 30 * time.Second
+)
+
+// Client выполняет запросы от имени пользователя Username: API определяет пользователя по параметру username
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	username   string
+	retries    int
+	backoff    time.Duration
+
+	Tenders   *TenderService
+	Bids      *BidService
+	Decisions *DecisionService
+	Reviews   *ReviewService
+}
+
+type Option func(*Client)
+
+// WithHTTPClient задаёт HTTP-клиент, например с собственным транспортом или таймаутом
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUsername задаёт пользователя, от имени которого выполняются запросы
+func WithUsername(username string) Option {
+	return func(c *Client) {
+		c.username = username
+	}
+}
+
+// WithRetries задаёт число повторов идемпотентных запросов (GET) и начальную паузу между ними,
+// которая удваивается с каждой попыткой. Ноль отключает повторы
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New создаёт клиент для сервера baseURL, например http://localhost:8080
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: base URL must be http or https, got %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.init()
+
+	return c, nil
+}
+
+// As возвращает клиент с теми же настройками, выполняющий запросы от имени другого пользователя
+func (c *Client) As(username string) *Client {
+	clone := *c
+	clone.username = username
+	clone.init()
+	return &clone
+}
+
+// Username возвращает пользователя, от имени которого выполняются запросы
+func (c *Client) Username() string {
+	return c.username
+}
+
+func (c *Client) init() {
+	c.Tenders = &TenderService{c}
+	c.Bids = &BidService{c}
+	c.Decisions = &DecisionService{c}
+	c.Reviews = &ReviewService{c}
+}
+
+// request - один вызов API. Параметр username добавляется автоматически, если задан у клиента
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+}
+
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	query := r.query
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.username != "" && !query.Has("username") {
+		query.Set("username", c.username)
+	}
+
+	u := *c.baseURL
+	u.Path += r.path
+	u.RawQuery = query.Encode()
+
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return err
+		}
+	}
+
+	// Повторяются только запросы на чтение: повтор изменяющего запроса мог бы применить изменение дважды
+	attempts := 1
+	if r.method == http.MethodGet {
+		attempts += c.retries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff<<(attempt-1)); err != nil {
+				return err
+			}
+		}
+
+		var retry bool
+		retry, err = c.send(ctx, r.method, u.String(), body, out)
+		if err == nil || !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// send выполняет один запрос и сообщает, имеет ли смысл его повторить
+func (c *Client) send(ctx context.Context, method, u string, body []byte, out any) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Сетевые ошибки повторяются, если запрос не отменён вызывающим
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := newError(resp.StatusCode, data)
+		return temporary(resp.StatusCode), apiErr
+	}
+
+	if out == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("client: decode %s response: %w", method, err)
+	}
+	return false, nil
+}
+
+func temporary(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Page - параметры страницы списка. Нулевые значения означают настройки сервера по умолчанию
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// all перебирает записи списка постранично. Сервер может уменьшить limit до своего максимума,
+// поэтому перебор заканчивается на пустой странице, а смещение растёт на число полученных записей
+func all[T any](ctx context.Context, page Page, fetch func(context.Context, Page) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, err := fetch(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if len(items) == 0 {
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			page.Offset += len(items)
+		}
+	}
+}
+
+func (p Page) values() url.Values {
+	v := url.Values{}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		v.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return v
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"myapp/config"
+	"myapp/database"
+	"myapp/health"
+	"myapp/models"
+	"myapp/router"
+	"myapp/seed"
+)
+
+// newTestServer запускает сервер API на базе TEST_POSTGRES_CONN с демонстрационными данными.
+// Схема применяется так же, как командой migrate; без TEST_POSTGRES_CONN тест пропускается
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	conn := os.Getenv("TEST_POSTGRES_CONN")
+	if conn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+
+	cfg, err := config.ReadConfig("")
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	cfg.DB.Conn = conn
+	cfg.HTTP.GinMode = gin.TestMode
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.Open(cfg.DB)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	f, err := seed.Demo()
+	if err != nil {
+		t.Fatalf("demo fixture: %v", err)
+	}
+	if _, err := seed.Load(context.Background(), db, f, cfg.Quorum); err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	srv := httptest.NewServer(router.SetupRouter(cfg, db, nil, nil, nil, nil, health.NewChecker(time.Second)))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, baseURL string, opts ...Option) *Client {
+	t.Helper()
+
+	c, err := New(baseURL, opts...)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return c
+}
+
+func TestErrors(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	anonymous := newTestClient(t, srv.URL, WithRetries(0, 0))
+	buyer := anonymous.As("buyer1")
+
+	var renovation uuid.UUID
+	for tender, err := range buyer.Tenders.AllMine(ctx) {
+		if err != nil {
+			t.Fatalf("list tenders: %v", err)
+		}
+		if tender.Name == "Office renovation" {
+			renovation = tender.ID
+		}
+	}
+	if renovation == uuid.Nil {
+		t.Fatal("demo tender Office renovation is not found")
+	}
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantStatus int
+		want       []error
+	}{
+		{
+			name:       "bad request",
+			call:       func() error { _, err := anonymous.Tenders.Mine(ctx, Page{}); return err },
+			wantStatus: http.StatusBadRequest,
+			want:       []error{ErrInvalid, ErrMissingParameter},
+		},
+		{
+			name:       "unauthorized",
+			call:       func() error { _, err := anonymous.As("nobody").Tenders.Mine(ctx, Page{}); return err },
+			wantStatus: http.StatusUnauthorized,
+			want:       []error{ErrUnauthorized, ErrUserNotFound},
+		},
+		{
+			name: "forbidden",
+			call: func() error {
+				_, err := anonymous.As("supplier1").Tenders.SetStatus(ctx, renovation, models.Published)
+				return err
+			},
+			wantStatus: http.StatusForbidden,
+			want:       []error{ErrForbidden, ErrNotAuthorized},
+		},
+		{
+			name:       "not found",
+			call:       func() error { _, err := buyer.Tenders.Status(ctx, uuid.New()); return err },
+			wantStatus: http.StatusNotFound,
+			want:       []error{ErrNotFound, ErrTenderNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", apiErr.StatusCode, tt.wantStatus)
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("errors.Is(%v, %v) = false", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAllEndsOnEmptyPage(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	buyer := newTestClient(t, srv.URL, WithUsername("buyer1"))
+
+	want, err := buyer.Tenders.Mine(ctx, Page{Limit: 100})
+	if err != nil {
+		t.Fatalf("list tenders: %v", err)
+	}
+	if len(want) < 2 {
+		t.Fatalf("got %d demo tenders, want at least 2 to cross a page", len(want))
+	}
+
+	var got []uuid.UUID
+	for tender, err := range all(ctx, Page{Limit: 1}, buyer.Tenders.Mine) {
+		if err != nil {
+			t.Fatalf("iterate tenders: %v", err)
+		}
+		got = append(got, tender.ID)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("iterated %d tenders, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i].ID {
+			t.Errorf("tender %d = %s, want %s", i, got[i], want[i].ID)
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if r.URL.Query().Get("fail") != "" || n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"reason": "Database is unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`"Published"`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newTestClient(t, srv.URL, WithUsername("buyer1"), WithRetries(3, time.Millisecond))
+	id := uuid.New()
+
+	status, err := c.Tenders.Status(ctx, id)
+	if err != nil || status != models.Published {
+		t.Fatalf("status = %q, %v, want Published after retries", status, err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("GET sent %d times, want 3", n)
+	}
+
+	requests.Store(0)
+	_, err = c.Tenders.SetStatus(ctx, id, models.Closed)
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("PUT error = %v, want ErrServer", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("PUT sent %d times, want 1: modifying requests must not be retried", n)
+	}
+
+	requests.Store(0)
+	err = c.do(ctx, request{method: http.MethodGet, path: "/api/ping", query: map[string][]string{"fail": {"1"}}}, nil)
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("GET error = %v, want ErrServer after retries run out", err)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("GET sent %d times, want 4", n)
+	}
+}
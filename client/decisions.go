@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"myapp/models"
+)
+
+// DecisionService - решения по предложениям
+type DecisionService struct {
+	client *Client
+}
+
+// Submit принимает решение по предложению. Для тендера с лотами решение принимается по лоту lotID,
+// для остальных тендеров lotID равен nil
+func (s *DecisionService) Submit(ctx context.Context, bidID uuid.UUID, decision models.DecisionType, lotID *uuid.UUID) (models.Bid, error) {
+	query := url.Values{"decision": {string(decision)}}
+	if lotID != nil {
+		query.Set("lotId", lotID.String())
+	}
+
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(bidID, "/submit_decision"), query: query}, &bid)
+	return bid, err
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Ошибки по коду ответа. Проверяются через errors.Is: errors.Is(err, client.ErrNotFound)
+var (
+	ErrInvalid      = errors.New("invalid request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrServer       = errors.New("server error")
+)
+
+// Ошибки по причине, которую возвращает API. Каждая из них также соответствует ошибке по коду ответа
+var (
+	ErrUserNotFound     = errors.New("user does not exist")
+	ErrTenderNotFound   = errors.New("tender not found")
+	ErrBidNotFound      = errors.New("bid not found")
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrLotNotFound      = errors.New("lot not found")
+	ErrVersionNotFound  = errors.New("version not found")
+	ErrNotAuthorized    = errors.New("user is not authorized")
+	ErrTenderNotOpen    = errors.New("tender is not published")
+	ErrBidNotPublished  = errors.New("bid is not published")
+	ErrBidWithdrawn     = errors.New("bid is withdrawn")
+	ErrBidsSealed       = errors.New("tender bids are sealed")
+	ErrBidsOpened       = errors.New("tender bids are already opened")
+	ErrReviewHasReply   = errors.New("review already has a reply")
+	ErrMissingParameter = errors.New("missing required parameter")
+)
+
+var reasons = map[string]error{
+	"User does not exist":                          ErrUserNotFound,
+	"Requester does not exist":                     ErrUserNotFound,
+	"Tender not found":                             ErrTenderNotFound,
+	"Bid not found":                                ErrBidNotFound,
+	"Review not found":                             ErrReviewNotFound,
+	"Lot not found":                                ErrLotNotFound,
+	"Tender version not found":                     ErrVersionNotFound,
+	"Bid version not found":                        ErrVersionNotFound,
+	"User is not authorized":                       ErrNotAuthorized,
+	"User is not authorized for this organization": ErrNotAuthorized,
+	"User is not authorized for this tender":       ErrNotAuthorized,
+	"User is not authorized for this bid":          ErrNotAuthorized,
+	"User is not authorized for this review":       ErrNotAuthorized,
+	"Tender is not published":                      ErrTenderNotOpen,
+	"Bid is not published":                         ErrBidNotPublished,
+	"Bid is withdrawn, use resubmit":               ErrBidWithdrawn,
+	"Tender bids are sealed":                       ErrBidsSealed,
+	"Tender bids are already opened":               ErrBidsOpened,
+	"Review already has a reply":                   ErrReviewHasReply,
+	"Missing required parameter(s)":                ErrMissingParameter,
+	"Missing required parameters":                  ErrMissingParameter,
+}
+
+// Error - ответ API с кодом ошибки. Reason содержит причину из поля reason
+type Error struct {
+	StatusCode int
+	Reason     string
+}
+
+func newError(status int, body []byte) *Error {
+	var payload struct {
+		Reason string `json:"reason"`
+		// Обработчик GET /api/bids/{id}/... сообщает о неизвестном действии в поле error
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	reason := payload.Reason
+	if reason == "" {
+		reason = payload.Error
+	}
+	if reason == "" {
+		reason = http.StatusText(status)
+	}
+
+	return &Error{StatusCode: status, Reason: reason}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Reason)
+}
+
+// Unwrap позволяет сравнивать ошибку как с ошибкой по коду ответа, так и с ошибкой по причине
+func (e *Error) Unwrap() []error {
+	var errs []error
+
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		errs = append(errs, ErrInvalid)
+	case e.StatusCode == http.StatusUnauthorized:
+		errs = append(errs, ErrUnauthorized)
+	case e.StatusCode == http.StatusForbidden:
+		errs = append(errs, ErrForbidden)
+	case e.StatusCode == http.StatusNotFound:
+		errs = append(errs, ErrNotFound)
+	case e.StatusCode == http.StatusConflict:
+		errs = append(errs, ErrConflict)
+	case e.StatusCode >= http.StatusInternalServerError:
+		errs = append(errs, ErrServer)
+	}
+
+	if err, ok := reasons[e.Reason]; ok {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+	"myapp/models"
+)
+
+// ReviewService - отзывы о предложениях и ответы на них
+type ReviewService struct {
+	client *Client
+}
+
+type ReviewInput struct {
+	Description   string `json:"description"`
+	Quality       int    `json:"quality"`
+	Timeliness    int    `json:"timeliness"`
+	Communication int    `json:"communication"`
+}
+
+// UpdateReviewInput - изменяемые поля отзыва; nil означает, что поле не меняется
+type UpdateReviewInput struct {
+	Description   *string `json:"description,omitempty"`
+	Quality       *int    `json:"quality,omitempty"`
+	Timeliness    *int    `json:"timeliness,omitempty"`
+	Communication *int    `json:"communication,omitempty"`
+}
+
+// ReviewListOptions - параметры списка отзывов по тендеру. При Scope "author" возвращаются
+// все отзывы об авторе AuthorUsername; Order - "asc" или "desc" по дате создания
+type ReviewListOptions struct {
+	Page
+	AuthorUsername string
+	Scope          string
+	Order          string
+}
+
+func reviewPath(id uuid.UUID, action string) string {
+	return "/api/reviews/" + id.String() + action
+}
+
+// Submit оставляет отзыв о предложении bidID
+func (s *ReviewService) Submit(ctx context.Context, bidID uuid.UUID, in ReviewInput) (models.Bid, error) {
+	var bid models.Bid
+	err := s.client.do(ctx, request{method: http.MethodPut, path: bidPath(bidID, "/feedback"), body: in}, &bid)
+	return bid, err
+}
+
+// List возвращает страницу отзывов по тендеру tenderID
+func (s *ReviewService) List(ctx context.Context, tenderID uuid.UUID, opts ReviewListOptions) ([]models.Review, error) {
+	query := opts.Page.values()
+	query.Set("requesterUsername", s.client.username)
+	if opts.AuthorUsername != "" {
+		query.Set("authorUsername", opts.AuthorUsername)
+	}
+	if opts.Scope != "" {
+		query.Set("scope", opts.Scope)
+	}
+	if opts.Order != "" {
+		query.Set("order", opts.Order)
+	}
+
+	var reviews []models.Review
+	err := s.client.do(ctx, request{method: http.MethodGet, path: bidPath(tenderID, "/reviews"), query: query}, &reviews)
+	return reviews, err
+}
+
+// All перебирает все отзывы по тендеру начиная со страницы opts.Page
+func (s *ReviewService) All(ctx context.Context, tenderID uuid.UUID, opts ReviewListOptions) iter.Seq2[models.Review, error] {
+	return all(ctx, opts.Page, func(ctx context.Context, p Page) ([]models.Review, error) {
+		opts.Page = p
+		return s.List(ctx, tenderID, opts)
+	})
+}
+
+// Mine возвращает страницу отзывов, оставленных пользователем
+func (s *ReviewService) Mine(ctx context.Context, page Page) ([]models.Review, error) {
+	var reviews []models.Review
+	err := s.client.do(ctx, request{method: http.MethodGet, path: "/api/reviews/my", query: page.values()}, &reviews)
+	return reviews, err
+}
+
+// AllMine перебирает все отзывы, оставленные пользователем
+func (s *ReviewService) AllMine(ctx context.Context) iter.Seq2[models.Review, error] {
+	return all(ctx, Page{}, s.Mine)
+}
+
+func (s *ReviewService) Update(ctx context.Context, id uuid.UUID, in UpdateReviewInput) (models.Review, error) {
+	var review models.Review
+	err := s.client.do(ctx, request{method: http.MethodPatch, path: reviewPath(id, ""), body: in}, &review)
+	return review, err
+}
+
+func (s *ReviewService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, request{method: http.MethodDelete, path: reviewPath(id, "")}, nil)
+}
+
+// Reply отвечает на отзыв от имени автора предложения
+func (s *ReviewService) Reply(ctx context.Context, id uuid.UUID, text string) (models.Review, error) {
+	var review models.Review
+	body := map[string]string{"text": text}
+	err := s.client.do(ctx, request{method: http.MethodPost, path: reviewPath(id, "/reply"), body: body}, &review)
+	return review, err
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"myapp/models"
+)
+
+// TenderService - операции с тендерами и их лотами
+type TenderService struct {
+	client *Client
+}
+
+type CreateTenderInput struct {
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	ServiceType    models.ServiceType `json:"serviceType"`
+	OrganizationID uuid.UUID          `json:"organizationId"`
+	// CreatorUsername по умолчанию - пользователь клиента
+	CreatorUsername string            `json:"creatorUsername"`
+	Sealed          bool              `json:"sealed"`
+	Deadline        *time.Time        `json:"deadline,omitempty"`
+	AuctionMode     bool              `json:"auctionMode"`
+	Visibility      models.Visibility `json:"visibility,omitempty"`
+	Lots            []LotInput        `json:"lots,omitempty"`
+}
+
+type LotInput struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Budget      float64 `json:"budget"`
+	Quantity    int     `json:"quantity"`
+}
+
+// EditTenderInput - изменяемые поля тендера; пустые поля не меняются
+type EditTenderInput struct {
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description,omitempty"`
+	ServiceType models.ServiceType `json:"serviceType,omitempty"`
+	Status      models.Status      `json:"status,omitempty"`
+	Deadline    *time.Time         `json:"deadline,omitempty"`
+}
+
+// TenderListOptions - фильтры списка опубликованных тендеров
+type TenderListOptions struct {
+	Page
+	ServiceTypes []models.ServiceType
+}
+
+func tenderPath(id uuid.UUID, action string) string {
+	return "/api/tenders/" + id.String() + action
+}
+
+// List возвращает страницу опубликованных тендеров, доступных пользователю клиента
+func (s *TenderService) List(ctx context.Context, opts TenderListOptions) ([]models.Tender, error) {
+	query := opts.Page.values()
+	for _, st := range opts.ServiceTypes {
+		query.Add("service_type", string(st))
+	}
+
+	var tenders []models.Tender
+	err := s.client.do(ctx, request{method: http.MethodGet, path: "/api/tenders", query: query}, &tenders)
+	return tenders, err
+}
+
+// All перебирает все опубликованные тендеры начиная со страницы opts.Page
+func (s *TenderService) All(ctx context.Context, opts TenderListOptions) iter.Seq2[models.Tender, error] {
+	return all(ctx, opts.Page, func(ctx context.Context, p Page) ([]models.Tender, error) {
+		opts.Page = p
+		return s.List(ctx, opts)
+	})
+}
+
+// Mine возвращает страницу тендеров организаций пользователя
+func (s *TenderService) Mine(ctx context.Context, page Page) ([]models.Tender, error) {
+	var tenders []models.Tender
+	err := s.client.do(ctx, request{method: http.MethodGet, path: "/api/tenders/my", query: page.values()}, &tenders)
+	return tenders, err
+}
+
+// AllMine перебирает все тендеры организаций пользователя
+func (s *TenderService) AllMine(ctx context.Context) iter.Seq2[models.Tender, error] {
+	return all(ctx, Page{}, s.Mine)
+}
+
+func (s *TenderService) Create(ctx context.Context, in CreateTenderInput) (models.Tender, error) {
+	if in.CreatorUsername == "" {
+		in.CreatorUsername = s.client.username
+	}
+
+	var tender models.Tender
+	err := s.client.do(ctx, request{method: http.MethodPost, path: "/api/tenders/new", body: in}, &tender)
+	return tender, err
+}
+
+func (s *TenderService) Status(ctx context.Context, id uuid.UUID) (models.Status, error) {
+	var status models.Status
+	err := s.client.do(ctx, request{method: http.MethodGet, path: tenderPath(id, "/status")}, &status)
+	return status, err
+}
+
+func (s *TenderService) SetStatus(ctx context.Context, id uuid.UUID, status models.Status) (models.Tender, error) {
+	var tender models.Tender
+	query := url.Values{"status": {string(status)}}
+	err := s.client.do(ctx, request{method: http.MethodPut, path: tenderPath(id, "/status"), query: query}, &tender)
+	return tender, err
+}
+
+func (s *TenderService) Edit(ctx context.Context, id uuid.UUID, in EditTenderInput) (models.Tender, error) {
+	var tender models.Tender
+	err := s.client.do(ctx, request{method: http.MethodPatch, path: tenderPath(id, "/edit"), body: in}, &tender)
+	return tender, err
+}
+
+// Rollback создаёт новую версию тендера с параметрами версии version
+func (s *TenderService) Rollback(ctx context.Context, id uuid.UUID, version int) (models.Tender, error) {
+	var tender models.Tender
+	err := s.client.do(ctx, request{method: http.MethodPut, path: tenderPath(id, "/rollback/"+strconv.Itoa(version))}, &tender)
+	return tender, err
+}
+
+// Open вскрывает предложения закрытого тендера до окончания срока подачи
+func (s *TenderService) Open(ctx context.Context, id uuid.UUID) (models.TenderOpening, error) {
+	var opening models.TenderOpening
+	err := s.client.do(ctx, request{method: http.MethodPut, path: tenderPath(id, "/open")}, &opening)
+	return opening, err
+}
+
+func (s *TenderService) CreateLot(ctx context.Context, id uuid.UUID, in LotInput) (models.TenderLot, error) {
+	var lot models.TenderLot
+	err := s.client.do(ctx, request{method: http.MethodPost, path: tenderPath(id, "/lots"), body: in}, &lot)
+	return lot, err
+}
+
+func (s *TenderService) Lots(ctx context.Context, id uuid.UUID) ([]models.TenderLot, error) {
+	var lots []models.TenderLot
+	err := s.client.do(ctx, request{method: http.MethodGet, path: tenderPath(id, "/lots")}, &lots)
+	return lots, err
+}
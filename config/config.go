@@ -1,38 +1,242 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Config - настройки сервера. Значения берутся по умолчанию, затем из YAML-файла
+// и в последнюю очередь из переменных окружения, которые имеют наивысший приоритет
 type Config struct {
-	ServerAddress string
-	PostgresConn  string
-	GinMode       string
+	HTTP       HTTP       `yaml:"http"`
+	GRPC       GRPC       `yaml:"grpc"`
+	DB         DB         `yaml:"db"`
+	Auth       Auth       `yaml:"auth"`
+	Pagination Pagination `yaml:"pagination"`
+	Quorum     Quorum     `yaml:"quorum"`
+	Scheduler  Scheduler  `yaml:"scheduler"`
+	SMTP       SMTP       `yaml:"smtp"`
+	Storage    Storage    `yaml:"storage"`
+	Contracts  Contracts  `yaml:"contracts"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Log        Log        `yaml:"log"`
 }
 
-func LoadConfig() (*Config, error) {
-	err := godotenv.Load()
+type HTTP struct {
+	Address           string        `yaml:"address"`
+	GinMode           string        `yaml:"ginMode"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	// Таймаут записи по умолчанию отключён: он обрывал бы потоки событий и скачивание больших вложений
+	WriteTimeout    time.Duration `yaml:"writeTimeout"`
+	IdleTimeout     time.Duration `yaml:"idleTimeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// Пауза между отказом проверки готовности и остановкой сервера, за которую балансировщик выводит экземпляр из ротации
+	ShutdownDrainDelay time.Duration `yaml:"shutdownDrainDelay"`
+	TLS                TLS           `yaml:"tls"`
+}
+
+// TLS включается, если заданы и сертификат, и ключ
+type TLS struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// GRPC - сервер gRPC API. Он использует сертификат TLS и таймаут остановки HTTP-сервера.
+// По умолчанию сервер слушает только локальный адрес, а reflection выключен: открыть их наружу нужно явно
+type GRPC struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+	// Reflection позволяет клиентам вроде grpcurl получать описание сервисов с сервера
+	Reflection bool `yaml:"reflection"`
+}
+
+type DB struct {
+	Conn               string        `yaml:"conn"`
+	MaxOpenConns       int           `yaml:"maxOpenConns"`
+	MaxIdleConns       int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime    time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime    time.Duration `yaml:"connMaxIdleTime"`
+	SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold"`
+}
+
+// Auth - учётные данные служебных эндпоинтов. Пользователи API по-прежнему определяются параметром username
+type Auth struct {
+	MetricsToken string `yaml:"metricsToken"`
+}
+
+type Pagination struct {
+	DefaultLimit int `yaml:"defaultLimit"`
+	MaxLimit     int `yaml:"maxLimit"`
+}
+
+// Quorum - число одобрений, необходимое для победы предложения: не больше MaxApprovals
+// и не больше числа ответственных лиц организации
+type Quorum struct {
+	MaxApprovals int `yaml:"maxApprovals"`
+}
+
+type Scheduler struct {
+	Interval           time.Duration `yaml:"interval"`
+	OpenSealedTenders  time.Duration `yaml:"openSealedTenders"`
+	CloseAuctionRounds time.Duration `yaml:"closeAuctionRounds"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type Storage struct {
+	Backend      string   `yaml:"backend"`
+	LocalDir     string   `yaml:"localDir"`
+	S3           S3       `yaml:"s3"`
+	MaxSize      int64    `yaml:"maxSize"`
+	AllowedTypes []string `yaml:"allowedTypes"`
+}
+
+type S3 struct {
+	Endpoint  string `yaml:"endpoint"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+	Bucket    string `yaml:"bucket"`
+	UseSSL    bool   `yaml:"useSSL"`
+}
+
+type Contracts struct {
+	FontPath string `yaml:"fontPath"`
+}
+
+type Metrics struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+type Tracing struct {
+	// Экспорт трассировок: none, otlp или stdout для локальной отладки
+	Exporter string `yaml:"exporter"`
+}
+
+type Log struct {
+	// Уровень журнала: debug, info, warn или error; формат: json или text
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// Типы вложений по умолчанию: документы, таблицы, изображения и архивы
+var defaultAttachmentTypes = []string{
+	"application/pdf",
+	"application/zip",
+	"application/msword",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"text/plain",
+	"text/csv",
+	"image/png",
+	"image/jpeg",
+}
+
+func defaults() Config {
+	return Config{
+		HTTP: HTTP{
+			Address:            "0.0.0.0:8080",
+			GinMode:            "release",
+			ReadHeaderTimeout:  10 * time.Second,
+			IdleTimeout:        2 * time.Minute,
+			ShutdownTimeout:    5 * time.Second,
+			ShutdownDrainDelay: 5 * time.Second,
+		},
+		GRPC: GRPC{Enabled: true, Address: "127.0.0.1:9090"},
+		DB: DB{
+			MaxOpenConns:       25,
+			MaxIdleConns:       10,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Pagination: Pagination{DefaultLimit: 5, MaxLimit: 100},
+		Quorum:     Quorum{MaxApprovals: 3},
+		Scheduler:  Scheduler{Interval: 30 * time.Second},
+		SMTP:       SMTP{Port: 587},
+		Storage: Storage{
+			Backend:      "local",
+			LocalDir:     "data/attachments",
+			MaxSize:      10 << 20,
+			AllowedTypes: defaultAttachmentTypes,
+		},
+		Contracts: Contracts{FontPath: "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"},
+		// Метрики по умолчанию доступны только с локального адреса, отдельно от публичного API
+		Metrics: Metrics{Address: "127.0.0.1:9091"},
+		Tracing: Tracing{Exporter: "none"},
+		Log:     Log{Level: "info", Format: "json"},
+	}
+}
+
+// LoadConfig собирает настройки из значений по умолчанию, YAML-файла path (если задан), файла .env
+// и переменных окружения и проверяет их. Все ошибки проверки возвращаются вместе
+func LoadConfig(path string) (*Config, error) {
+	cfg, err := ReadConfig(path)
 	if err != nil {
-		//log.Fatal("Error loading .env file")
+		return nil, err
 	}
 
-	serverAddress := os.Getenv("SERVER_ADDRESS")
-	if serverAddress == "" {
-		serverAddress = "0.0.0.0:8080"
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode == "" {
-		ginMode = "release"
+	return cfg, nil
+}
+
+// ReadConfig собирает настройки так же, как LoadConfig, но не проверяет их
+func ReadConfig(path string) (*Config, error) {
+	// Файл .env необязателен, но ошибка чтения существующего файла не должна теряться
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
 	}
 
-	config := &Config{
-		ServerAddress: serverAddress,
-		PostgresConn:  os.Getenv("POSTGRES_CONN"),
-		GinMode:       ginMode,
+	cfg := defaults()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Список типов из файла заменяет список по умолчанию, а не дополняет его
+		cfg.Storage.AllowedTypes = nil
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.Storage.AllowedTypes == nil {
+			cfg.Storage.AllowedTypes = defaultAttachmentTypes
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, err
+	}
+
+	// Интервалы отдельных задач по умолчанию совпадают с общим интервалом планировщика
+	if cfg.Scheduler.OpenSealedTenders == 0 {
+		cfg.Scheduler.OpenSealedTenders = cfg.Scheduler.Interval
+	}
+	if cfg.Scheduler.CloseAuctionRounds == 0 {
+		cfg.Scheduler.CloseAuctionRounds = cfg.Scheduler.Interval
 	}
 
-	return config, nil
+	return &cfg, nil
 }
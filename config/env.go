@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envLoader переопределяет настройки переменными окружения и накапливает ошибки разбора значений
+type envLoader struct {
+	errs []error
+}
+
+func (l *envLoader) lookup(name string) (string, bool) {
+	value, ok := os.LookupEnv(name)
+	return value, ok && value != ""
+}
+
+func (l *envLoader) string(target *string, name string) {
+	if value, ok := l.lookup(name); ok {
+		*target = value
+	}
+}
+
+func (l *envLoader) int(target *int, name string) {
+	if value, ok := l.lookup(name); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid integer %q", name, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (l *envLoader) int64(target *int64, name string) {
+	if value, ok := l.lookup(name); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid integer %q", name, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (l *envLoader) bool(target *bool, name string) {
+	if value, ok := l.lookup(name); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid boolean %q", name, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (l *envLoader) duration(target *time.Duration, name string) {
+	if value, ok := l.lookup(name); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid duration %q", name, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+// list разбирает значения, перечисленные через запятую
+func (l *envLoader) list(target *[]string, name string) {
+	if value, ok := l.lookup(name); ok {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*target = items
+	}
+}
+
+func applyEnv(cfg *Config) error {
+	var l envLoader
+
+	l.string(&cfg.HTTP.Address, "SERVER_ADDRESS")
+	l.string(&cfg.HTTP.GinMode, "GIN_MODE")
+	l.duration(&cfg.HTTP.ReadTimeout, "HTTP_READ_TIMEOUT")
+	l.duration(&cfg.HTTP.ReadHeaderTimeout, "HTTP_READ_HEADER_TIMEOUT")
+	l.duration(&cfg.HTTP.WriteTimeout, "HTTP_WRITE_TIMEOUT")
+	l.duration(&cfg.HTTP.IdleTimeout, "HTTP_IDLE_TIMEOUT")
+	l.duration(&cfg.HTTP.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	l.duration(&cfg.HTTP.ShutdownDrainDelay, "SHUTDOWN_DRAIN_DELAY")
+	l.string(&cfg.HTTP.TLS.CertFile, "TLS_CERT_FILE")
+	l.string(&cfg.HTTP.TLS.KeyFile, "TLS_KEY_FILE")
+
+	l.bool(&cfg.GRPC.Enabled, "GRPC_ENABLED")
+	l.string(&cfg.GRPC.Address, "GRPC_ADDRESS")
+	l.bool(&cfg.GRPC.Reflection, "GRPC_REFLECTION")
+
+	l.string(&cfg.DB.Conn, "POSTGRES_CONN")
+	l.int(&cfg.DB.MaxOpenConns, "DB_MAX_OPEN_CONNS")
+	l.int(&cfg.DB.MaxIdleConns, "DB_MAX_IDLE_CONNS")
+	l.duration(&cfg.DB.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME")
+	l.duration(&cfg.DB.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME")
+	l.duration(&cfg.DB.SlowQueryThreshold, "DB_SLOW_QUERY_THRESHOLD")
+
+	l.string(&cfg.Auth.MetricsToken, "METRICS_TOKEN")
+
+	l.int(&cfg.Pagination.DefaultLimit, "PAGINATION_DEFAULT_LIMIT")
+	l.int(&cfg.Pagination.MaxLimit, "PAGINATION_MAX_LIMIT")
+
+	l.int(&cfg.Quorum.MaxApprovals, "QUORUM_MAX_APPROVALS")
+
+	l.duration(&cfg.Scheduler.Interval, "SCHEDULER_INTERVAL")
+	l.duration(&cfg.Scheduler.OpenSealedTenders, "SCHEDULER_OPEN_SEALED_TENDERS")
+	l.duration(&cfg.Scheduler.CloseAuctionRounds, "SCHEDULER_CLOSE_AUCTION_ROUNDS")
+
+	l.string(&cfg.SMTP.Host, "SMTP_HOST")
+	l.int(&cfg.SMTP.Port, "SMTP_PORT")
+	l.string(&cfg.SMTP.Username, "SMTP_USERNAME")
+	l.string(&cfg.SMTP.Password, "SMTP_PASSWORD")
+	l.string(&cfg.SMTP.From, "SMTP_FROM")
+
+	l.string(&cfg.Storage.Backend, "BLOB_STORE")
+	l.string(&cfg.Storage.LocalDir, "BLOB_LOCAL_DIR")
+	l.string(&cfg.Storage.S3.Endpoint, "S3_ENDPOINT")
+	l.string(&cfg.Storage.S3.AccessKey, "S3_ACCESS_KEY")
+	l.string(&cfg.Storage.S3.SecretKey, "S3_SECRET_KEY")
+	l.string(&cfg.Storage.S3.Bucket, "S3_BUCKET")
+	l.bool(&cfg.Storage.S3.UseSSL, "S3_USE_SSL")
+	l.int64(&cfg.Storage.MaxSize, "ATTACHMENT_MAX_SIZE")
+	l.list(&cfg.Storage.AllowedTypes, "ATTACHMENT_ALLOWED_TYPES")
+
+	l.string(&cfg.Contracts.FontPath, "CONTRACT_FONT_PATH")
+
+	l.bool(&cfg.Metrics.Enabled, "METRICS_ENABLED")
+	l.string(&cfg.Metrics.Address, "METRICS_ADDRESS")
+
+	l.string(&cfg.Tracing.Exporter, "OTEL_TRACES_EXPORTER")
+
+	l.string(&cfg.Log.Level, "LOG_LEVEL")
+	l.string(&cfg.Log.Format, "LOG_FORMAT")
+
+	return errors.Join(l.errs...)
+}
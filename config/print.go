@@ -0,0 +1,54 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Пароли в строке подключения вида "key=value": значение может быть в кавычках с экранированными символами,
+// а вокруг "=" допускаются пробелы
+var dsnPassword = regexp.MustCompile(`\b((?:ssl)?password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Пароли в параметрах строки подключения вида URL
+var urlPassword = regexp.MustCompile(`(^|&)((?:ssl)?password=)[^&]*`)
+
+// Redacted возвращает копию настроек, в которой пароли, токены и ключи заменены заглушкой
+func (c Config) Redacted() Config {
+	c.DB.Conn = redactConn(c.DB.Conn)
+	c.Auth.MetricsToken = redactValue(c.Auth.MetricsToken)
+	c.SMTP.Password = redactValue(c.SMTP.Password)
+	c.Storage.S3.SecretKey = redactValue(c.Storage.S3.SecretKey)
+	return c
+}
+
+// Print выводит действующие настройки в формате YAML без секретов
+func (c Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func redactValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactConn скрывает пароли в строке подключения, оставляя адрес и имя базы для диагностики.
+// Пароль может быть задан в адресе, параметрами password и sslpassword или парами key=value
+func redactConn(conn string) string {
+	if u, err := url.Parse(conn); err == nil && u.Scheme != "" {
+		u.RawQuery = urlPassword.ReplaceAllString(u.RawQuery, "${1}${2}"+redacted)
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(conn, "${1}"+redacted)
+}
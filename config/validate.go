@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+)
+
+// Validate проверяет согласованность настроек и возвращает все найденные ошибки сразу
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validAddress(c.HTTP.Address), "http.address: invalid address %q", c.HTTP.Address)
+	check(slices.Contains([]string{"debug", "release", "test"}, c.HTTP.GinMode), "http.ginMode: must be debug, release or test")
+	check(c.HTTP.ReadTimeout >= 0, "http.readTimeout: must not be negative")
+	check(c.HTTP.ReadHeaderTimeout >= 0, "http.readHeaderTimeout: must not be negative")
+	check(c.HTTP.WriteTimeout >= 0, "http.writeTimeout: must not be negative")
+	check(c.HTTP.IdleTimeout >= 0, "http.idleTimeout: must not be negative")
+	check(c.HTTP.ShutdownDrainDelay >= 0, "http.shutdownDrainDelay: must not be negative")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdownTimeout: must be positive")
+	check((c.HTTP.TLS.CertFile == "") == (c.HTTP.TLS.KeyFile == ""), "http.tls: certFile and keyFile must be set together")
+
+	if c.GRPC.Enabled {
+		check(validAddress(c.GRPC.Address), "grpc.address: invalid address %q", c.GRPC.Address)
+		check(c.GRPC.Address != c.HTTP.Address, "grpc.address: must differ from http.address")
+	}
+
+	check(c.DB.Conn != "", "db.conn: required (POSTGRES_CONN)")
+	check(c.DB.MaxOpenConns >= 0, "db.maxOpenConns: must not be negative")
+	check(c.DB.MaxIdleConns >= 0, "db.maxIdleConns: must not be negative")
+	check(c.DB.MaxOpenConns == 0 || c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "db.maxIdleConns: must not exceed db.maxOpenConns")
+	check(c.DB.ConnMaxLifetime >= 0, "db.connMaxLifetime: must not be negative")
+	check(c.DB.ConnMaxIdleTime >= 0, "db.connMaxIdleTime: must not be negative")
+	check(c.DB.SlowQueryThreshold >= 0, "db.slowQueryThreshold: must not be negative")
+
+	check(c.Pagination.DefaultLimit > 0, "pagination.defaultLimit: must be positive")
+	check(c.Pagination.MaxLimit >= c.Pagination.DefaultLimit, "pagination.maxLimit: must not be less than pagination.defaultLimit")
+
+	check(c.Quorum.MaxApprovals > 0, "quorum.maxApprovals: must be positive")
+
+	check(c.Scheduler.Interval > 0, "scheduler.interval: must be positive")
+	check(c.Scheduler.OpenSealedTenders > 0, "scheduler.openSealedTenders: must be positive")
+	check(c.Scheduler.CloseAuctionRounds > 0, "scheduler.closeAuctionRounds: must be positive")
+
+	if c.SMTP.Host != "" {
+		check(c.SMTP.Port > 0 && c.SMTP.Port <= 65535, "smtp.port: invalid port %d", c.SMTP.Port)
+		check(c.SMTP.From != "", "smtp.from: required when smtp.host is set")
+	}
+
+	switch c.Storage.Backend {
+	case "local":
+		check(c.Storage.LocalDir != "", "storage.localDir: required for local storage")
+	case "s3":
+		check(c.Storage.S3.Endpoint != "", "storage.s3.endpoint: required for s3 storage")
+		check(c.Storage.S3.Bucket != "", "storage.s3.bucket: required for s3 storage")
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend: unknown blob store %q", c.Storage.Backend))
+	}
+	check(c.Storage.MaxSize > 0, "storage.maxSize: must be positive")
+	check(len(c.Storage.AllowedTypes) > 0, "storage.allowedTypes: must not be empty")
+
+	if c.Metrics.Enabled {
+		check(validAddress(c.Metrics.Address), "metrics.address: invalid address %q", c.Metrics.Address)
+	}
+
+	check(slices.Contains([]string{"none", "otlp", "stdout"}, c.Tracing.Exporter), "tracing.exporter: must be none, otlp or stdout")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level: invalid level %q", c.Log.Level)
+	check(slices.Contains([]string{"json", "text"}, c.Log.Format), "log.format: must be json or text")
+
+	return errors.Join(errs...)
+}
+
+func validAddress(address string) bool {
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && port != ""
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+// isResponsible проверяет, что пользователь является ответственным лицом организации
+func isResponsible(db *gorm.DB, userID, organizationID uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Model(&models.OrganizationResponsible{}).Where("user_id = ? AND organization_id = ?", userID, organizationID).Count(&count).Error
+	return count > 0, err
+}
+
+// canViewTender проверяет доступ к тендеру: ответственные лица организации видят тендер в любом статусе,
+// остальные пользователи - только опубликованный, как в списке тендеров, а закрытый - только по приглашению
+func canViewTender(db *gorm.DB, tender models.Tender, employee models.Employee) (bool, error) {
+	if tender.Status == models.Published && tender.Visibility != models.VisibilityInviteOnly {
+		return true, nil
+	}
+
+	if responsible, err := isResponsible(db, employee.ID, tender.OrganizationID); err != nil || responsible {
+		return responsible, err
+	}
+
+	if tender.Status != models.Published {
+		return false, nil
+	}
+
+	return isInvited(db, tender, employee)
+}
+
+// isInvited проверяет, приглашён ли пользователь в тендер лично или как ответственное лицо приглашённой организации
+func isInvited(db *gorm.DB, tender models.Tender, employee models.Employee) (bool, error) {
+	var count int64
+	err := db.Model(&models.TenderInvitation{}).Where("tender_id = ?", tender.ID).Where(invitedScope(db, employee.ID)).Count(&count).Error
+	return count > 0, err
+}
+
+// invitedScope - условие на приглашения пользователя, общее для проверки доступа и списка тендеров
+func invitedScope(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Where("employee_id = ?", userID).Or("organization_id IN (?)", db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", userID))
+}
+
+// isBidOwner проверяет, что пользователь - автор предложения или, для предложений от организации,
+// ответственное лицо той же организации
+func isBidOwner(db *gorm.DB, bid models.Bid, employee models.Employee) (bool, error) {
+	if bid.AuthorID == employee.ID {
+		return true, nil
+	}
+
+	if bid.AuthorType != models.AuthorOrganization {
+		return false, nil
+	}
+
+	var count int64
+	err := db.Model(&models.OrganizationResponsible{}).Where("user_id = ? AND organization_id IN (?)", employee.ID, db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", bid.AuthorID)).Count(&count).Error
+	return count > 0, err
+}
+
+// canViewBid проверяет доступ к предложению: владельцы видят его всегда,
+// ответственные лица организации тендера - только опубликованное или отозванное и не запечатанное
+func canViewBid(db *gorm.DB, bid models.Bid, tender models.Tender, employee models.Employee) (bool, error) {
+	if owner, err := isBidOwner(db, bid, employee); err != nil || owner {
+		return owner, err
+	}
+
+	if bid.Status != models.BidPublished && bid.Status != models.BidWithdrawn {
+		return false, nil
+	}
+
+	if sealed, err := bidsSealed(db, tender); err != nil || sealed {
+		return false, err
+	}
+
+	return isResponsible(db, employee.ID, tender.OrganizationID)
+}
+
+// bidsSealed проверяет, скрыто ли содержимое предложений тендера: предложения запечатанного тендера
+// остаются скрытыми до истечения срока подачи или ручного вскрытия
+func bidsSealed(db *gorm.DB, tender models.Tender) (bool, error) {
+	if !tender.Sealed {
+		return false, nil
+	}
+
+	if tender.Deadline != nil && !time.Now().Before(*tender.Deadline) {
+		return false, nil
+	}
+
+	var count int64
+	err := db.Model(&models.TenderOpening{}).Where("tender_id = ?", tender.ID).Count(&count).Error
+	return count == 0, err
+}
+
+// bidsOpened проверяет, вскрыты ли предложения запечатанного тендера. После вскрытия ответственные лица видят
+// предложения конкурентов, поэтому подавать, менять и возвращать предложения уже нельзя
+func bidsOpened(db *gorm.DB, tender models.Tender) (bool, error) {
+	if !tender.Sealed {
+		return false, nil
+	}
+
+	sealed, err := bidsSealed(db, tender)
+	return !sealed && err == nil, err
+}
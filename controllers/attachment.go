@@ -0,0 +1,460 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+	"myapp/storage"
+)
+
+// Запас на заголовки multipart поверх максимального размера файла
+const multipartOverhead = 1 << 20
+
+type AttachmentController struct {
+	DB           *gorm.DB
+	Store        storage.BlobStore
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+func (ctrl AttachmentController) UploadTenderAttachment(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if ok, err := isResponsible(db, employee.ID, tender.OrganizationID); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	ctrl.upload(c, models.Attachment{
+		TenderID:   &tender.ID,
+		Version:    tender.Version,
+		UploadedBy: employee.ID,
+	}, "tenders/"+tender.ID.String())
+}
+
+func (ctrl AttachmentController) GetTenderAttachments(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var attachments []models.Attachment
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+	versionStr := c.Query("version")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	query := db.Order("created_at").Where("tender_id = ?", tender.ID)
+
+	if versionStr != "" {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid version parameter"})
+			logError(c, err, "Invalid version parameter")
+			return
+		}
+		query = query.Where("version = ?", version)
+	}
+
+	if err := query.Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve attachments"})
+		logError(c, err, "Failed to retrieve attachments")
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (ctrl AttachmentController) DownloadTenderAttachment(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var attachment models.Attachment
+
+	tenderID := c.Param("tenderId")
+	attachmentID := c.Param("attachmentId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	// Проверка существования вложения
+	if err := db.Where("id = ? AND tender_id = ?", attachmentID, tender.ID).First(&attachment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Attachment not found"})
+		logError(c, err, "Attachment not found")
+		return
+	}
+
+	ctrl.download(c, attachment)
+}
+
+func (ctrl AttachmentController) UploadBidAttachment(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var employee models.Employee
+
+	bidID := c.Param("bidID")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка авторизации
+	if ok, err := isBidOwner(db, bid, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	ctrl.upload(c, models.Attachment{
+		BidID:      &bid.ID,
+		Version:    bid.Version,
+		UploadedBy: employee.ID,
+	}, "bids/"+bid.ID.String())
+}
+
+func (ctrl AttachmentController) GetBidAttachments(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var attachments []models.Attachment
+
+	bidID := c.Param("id")
+	username := c.Query("username")
+	versionStr := c.Query("version")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к предложению
+	if ok, err := canViewBid(db, bid, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	query := db.Order("created_at").Where("bid_id = ?", bid.ID)
+
+	if versionStr != "" {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid version parameter"})
+			logError(c, err, "Invalid version parameter")
+			return
+		}
+		query = query.Where("version = ?", version)
+	}
+
+	if err := query.Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve attachments"})
+		logError(c, err, "Failed to retrieve attachments")
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (ctrl AttachmentController) DownloadBidAttachment(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var attachment models.Attachment
+
+	bidID := c.Param("id")
+	attachmentID := c.Param("attachmentId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к предложению
+	if ok, err := canViewBid(db, bid, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	// Проверка существования вложения
+	if err := db.Where("id = ? AND bid_id = ?", attachmentID, bid.ID).First(&attachment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Attachment not found"})
+		logError(c, err, "Attachment not found")
+		return
+	}
+
+	ctrl.download(c, attachment)
+}
+
+// upload принимает файл из поля file, проверяет его размер и тип, сохраняет содержимое в хранилище
+// и записывает вложение в базу данных
+func (ctrl AttachmentController) upload(c *gin.Context, attachment models.Attachment, keyPrefix string) {
+	db := requestDB(c, ctrl.DB)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ctrl.MaxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": "File is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing file"})
+		return
+	}
+
+	// Проверка размера файла
+	if fileHeader.Size > ctrl.MaxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"reason": "File is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid file"})
+		logError(c, err, "Invalid file")
+		return
+	}
+	defer file.Close()
+
+	// Тип определяется по содержимому файла, а не по заголовку от клиента
+	mtype, err := mimetype.DetectReader(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid file"})
+		logError(c, err, "Invalid file")
+		return
+	}
+
+	if !ctrl.allowed(mtype) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"reason": "File type is not allowed"})
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to read file"})
+		logError(c, err, "Failed to read file")
+		return
+	}
+
+	fileName := filepath.Base(fileHeader.Filename)
+	if len(fileName) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "File name is too long"})
+		return
+	}
+
+	attachment.ID = uuid.New()
+	attachment.FileName = fileName
+	attachment.ContentType = mtype.String()
+	attachment.Size = fileHeader.Size
+	attachment.StorageKey = keyPrefix + "/" + attachment.ID.String()
+
+	// Контрольная сумма считается во время записи в хранилище
+	hasher := sha256.New()
+	if err := ctrl.Store.Put(c.Request.Context(), attachment.StorageKey, io.TeeReader(file, hasher), attachment.Size, attachment.ContentType); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to store file"})
+		logError(c, err, "Failed to store file")
+		return
+	}
+	attachment.Checksum = hex.EncodeToString(hasher.Sum(nil))
+
+	if err := db.Create(&attachment).Error; err != nil {
+		if deleteErr := ctrl.Store.Delete(c.Request.Context(), attachment.StorageKey); deleteErr != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to delete orphaned attachment", "storage_key", attachment.StorageKey, "error", deleteErr)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create attachment"})
+		logError(c, err, "Failed to create attachment")
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (ctrl AttachmentController) allowed(mtype *mimetype.MIME) bool {
+	for _, allowedType := range ctrl.AllowedTypes {
+		if mtype.Is(allowedType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ctrl AttachmentController) download(c *gin.Context, attachment models.Attachment) {
+	reader, err := ctrl.Store.Get(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Attachment content not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to read attachment"})
+		logError(c, err, "Failed to read attachment")
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"ETag":                `"` + attachment.Checksum + `"`,
+		"X-Checksum-Sha256":   attachment.Checksum,
+	})
+}
@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/events"
+	"myapp/models"
+)
+
+const (
+	minRoundDuration = time.Minute
+	maxRoundDuration = 24 * time.Hour
+)
+
+// errPriceNotLower возвращается из транзакции, если цену предложения уже понизили до price или ниже
+var errPriceNotLower = errors.New("price is not lower than the current bid price")
+
+type AuctionController struct {
+	DB *gorm.DB
+}
+
+func (ctrl AuctionController) OpenRound(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+	var lastRound models.AuctionRound
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+	durationStr := c.Query("duration")
+
+	// Проверка обязательных параметров
+	if username == "" || durationStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration < minRoundDuration || duration > maxRoundDuration {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid duration parameter"})
+		logError(c, err, "Invalid duration parameter")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Проверка режима и статуса тендера
+	if !tender.AuctionMode {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not in auction mode"})
+		return
+	}
+
+	if tender.Status != models.Published {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+		return
+	}
+
+	// Одновременно открыт только один раунд
+	err = db.Where("tender_id = ?", tender.ID).Order("number DESC").First(&lastRound).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve rounds"})
+		logError(c, err, "Failed to retrieve rounds")
+		return
+	}
+
+	if err == nil && lastRound.Status == models.RoundOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Round is already open"})
+		return
+	}
+
+	// Раунд открывается только после поступления первоначальных предложений
+	bestPrice, err := ctrl.bestPrice(db, tender)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+		logError(c, err, "Failed to retrieve bids")
+		return
+	}
+
+	if bestPrice == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender has no published bids"})
+		return
+	}
+
+	round := models.AuctionRound{
+		ID:        uuid.New(),
+		TenderID:  tender.ID,
+		Number:    lastRound.Number + 1,
+		Status:    models.RoundOpen,
+		BestPrice: bestPrice,
+		EndsAt:    time.Now().Add(duration),
+	}
+
+	// Параллельно открытый раунд отсекают уникальные индексы по номеру раунда и по открытому раунду тендера
+	if err := db.Omit("Tender").Create(&round).Error; err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Round is already open"})
+			logError(c, err, "Round is already open")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to open round"})
+		logError(c, err, "Failed to open round")
+		return
+	}
+
+	if err := events.AuctionRoundChanged(db, round, tender); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to publish auction round event", "error", err)
+	}
+
+	c.JSON(http.StatusOK, round)
+}
+
+func (ctrl AuctionController) GetRounds(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var rounds []models.AuctionRound
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	if err := db.Order("number").Where("tender_id = ?", tender.ID).Find(&rounds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve rounds"})
+		logError(c, err, "Failed to retrieve rounds")
+		return
+	}
+
+	c.JSON(http.StatusOK, rounds)
+}
+
+func (ctrl AuctionController) LowerBidPrice(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var round models.AuctionRound
+
+	bidID := c.Param("bidID")
+	username := c.Query("username")
+	priceStr := c.Query("price")
+
+	// Проверка обязательных параметров
+	if username == "" || priceStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil || price <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid price parameter"})
+		logError(c, err, "Invalid price parameter")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка авторизации
+	if ok, err := isBidOwner(db, bid, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	// Проверка статуса предложения
+	if bid.Status != models.BidPublished {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not published"})
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	if !tender.AuctionMode || tender.Status != models.Published {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender auction is not running"})
+		return
+	}
+
+	// Цену можно менять только в открытом раунде, даже если планировщик ещё не успел его закрыть
+	if err := db.Where("tender_id = ? AND status = ? AND ends_at > ?", tender.ID, models.RoundOpen, time.Now()).First(&round).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "No open auction round"})
+		logError(c, err, "No open auction round")
+		return
+	}
+
+	// В обратном аукционе цена может только понижаться
+	if bid.Price != nil && price >= *bid.Price {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Price must be lower than the current bid price"})
+		return
+	}
+
+	// Цена предложения и лучшая цена раунда меняются условными обновлениями в одной транзакции,
+	// чтобы одновременные понижения не подняли ни цену предложения, ни лучшую цену раунда
+	var improved bool
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Изменение цены проходит через триггер истории и создаёт новую версию предложения
+		result := tx.Model(&models.Bid{}).Where("id = ? AND (price IS NULL OR price > ?)", bid.ID, price).Update("price", price)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPriceNotLower
+		}
+
+		result = tx.Model(&models.AuctionRound{}).Where("id = ? AND (best_price IS NULL OR best_price > ?)", round.ID, price).Update("best_price", price)
+		if result.Error != nil {
+			return result.Error
+		}
+		improved = result.RowsAffected > 0
+		return nil
+	})
+	if errors.Is(err, errPriceNotLower) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Price must be lower than the current bid price"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update bid price"})
+		logError(c, err, "Failed to update bid price")
+		return
+	}
+
+	if improved {
+		round.BestPrice = &price
+		if err := events.AuctionPriceChanged(db, round, tender); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish auction price event", "error", err)
+		}
+	}
+
+	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload bid"})
+		logError(c, err, "Failed to reload bid")
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+// bestPrice возвращает наименьшую цену среди опубликованных предложений тендера
+func (ctrl AuctionController) bestPrice(db *gorm.DB, tender models.Tender) (*float64, error) {
+	var bestPrice *float64
+	err := db.Model(&models.Bid{}).Select("MIN(price)").Where("tender_id = ? AND status = ?", tender.ID, models.BidPublished).Scan(&bestPrice).Error
+	return bestPrice, err
+}
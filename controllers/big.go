@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"log/slog"
+	"myapp/events"
 	"myapp/models"
+	"myapp/notify"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,10 +12,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"myapp/config"
 )
 
 type BidController struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Notifications *notify.Service
+	Pagination    config.Pagination
 }
 
 type CreateBidRequest struct {
@@ -21,6 +27,22 @@ type CreateBidRequest struct {
 	TenderID    uuid.UUID         `json:"tenderId" binding:"required"`
 	AuthorType  models.AuthorType `json:"authorType" binding:"required"`
 	AuthorID    uuid.UUID         `json:"authorId" binding:"required"`
+	// OrganizationID - организация, от имени которой подаётся предложение. Обязательна, если автор -
+	// ответственное лицо нескольких организаций
+	OrganizationID *uuid.UUID      `json:"organizationId,omitempty"`
+	Price          *float64        `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Lots           []BidLotRequest `json:"lots,omitempty" binding:"dive"`
+}
+
+type BidLotRequest struct {
+	LotID uuid.UUID `json:"lotId" binding:"required"`
+	Price float64   `json:"price" binding:"gt=0"`
+}
+
+type SealedBidsResponse struct {
+	Sealed   bool       `json:"sealed"`
+	Count    int64      `json:"count"`
+	Deadline *time.Time `json:"deadline,omitempty"`
 }
 
 type UpdateBidRequest struct {
@@ -30,25 +52,29 @@ type UpdateBidRequest struct {
 }
 
 func (ctrl BidController) CreateBid(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var req CreateBidRequest
 	var tender models.Tender
 	var employee models.Employee
-	var orgResp models.OrganizationResponsible
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
 		return
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("id = ?", req.AuthorID).First(&employee).Error; err != nil {
+	if err := db.Where("id = ?", req.AuthorID).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", req.TenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", req.TenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
@@ -58,29 +84,130 @@ func (ctrl BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	// Проверка авторизации
-	if req.AuthorType == models.AuthorOrganization {
-		// Проверка, что пользователь является ответственным лицом какой-либо организации
-		if err := ctrl.DB.Where("user_id = ?", req.AuthorID).First(&orgResp).Error; err != nil {
+	// После вскрытия запечатанного тендера предложение подавалось бы с оглядкой на предложения конкурентов
+	if opened, err := bidsOpened(db, tender); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tender openings"})
+		logError(c, err, "Failed to retrieve tender openings")
+		return
+	} else if opened {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are already opened"})
+		return
+	}
+
+	// В закрытый тендер предложения подают только приглашённые участники
+	if tender.Visibility == models.VisibilityInviteOnly {
+		invited, err := isInvited(db, tender, employee)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check invitation"})
+			logError(c, err, "Failed to check invitation")
+			return
+		}
+
+		if !invited {
+			c.JSON(http.StatusForbidden, gin.H{"reason": "User is not invited to this tender"})
+			return
+		}
+	}
+
+	// Проверка авторизации: предложение от организации подаёт её ответственное лицо
+	var organizationID *uuid.UUID
+	if req.AuthorType == models.AuthorOrganization && req.OrganizationID != nil {
+		responsible, err := isResponsible(db, req.AuthorID, *req.OrganizationID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check organization"})
+			logError(c, err, "Failed to check organization")
+			return
+		}
+
+		if !responsible {
+			c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+			return
+		}
+		organizationID = req.OrganizationID
+	} else if req.AuthorType == models.AuthorOrganization {
+		// Без organizationId организация определяется однозначно, только если она у автора одна
+		var orgResps []models.OrganizationResponsible
+		if err := db.Where("user_id = ?", req.AuthorID).Limit(2).Find(&orgResps).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve organizations"})
+			logError(c, err, "Failed to retrieve organizations")
+			return
+		}
+
+		if len(orgResps) == 0 {
 			c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for any organization"})
 			return
 		}
+		if len(orgResps) > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Organization is required for responsibles of several organizations"})
+			return
+		}
+		organizationID = &orgResps[0].OrganizationID
+	}
+
+	// В тендере с обратным аукционом цена предложения обязательна, с неё начинается понижение
+	if tender.AuctionMode && req.Price == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Price is required for auction tenders"})
+		return
+	}
+
+	// Проверка лотов: предложение на многолотовый тендер указывает цену хотя бы по одному открытому лоту
+	var lots []models.TenderLot
+	if err := db.Where("tender_id = ?", tender.ID).Find(&lots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve lots"})
+		logError(c, err, "Failed to retrieve lots")
+		return
+	}
+
+	if len(lots) > 0 && len(req.Lots) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid must target at least one lot"})
+		return
+	}
+
+	if len(lots) == 0 && len(req.Lots) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender has no lots"})
+		return
+	}
+
+	openLots := make(map[uuid.UUID]bool, len(lots))
+	for _, lot := range lots {
+		openLots[lot.ID] = lot.Status == models.LotOpen
+	}
+
+	var bidLots []models.BidLot
+	for _, lotReq := range req.Lots {
+		if !openLots[lotReq.LotID] {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid lot"})
+			return
+		}
+		// Каждый лот указывается не более одного раза
+		openLots[lotReq.LotID] = false
+
+		bidLots = append(bidLots, models.BidLot{
+			ID:     uuid.New(),
+			LotID:  lotReq.LotID,
+			Price:  lotReq.Price,
+			Status: models.BidLotPending,
+		})
 	}
 
 	bid := models.Bid{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      models.BidCreated,
-		TenderID:    req.TenderID,
-		AuthorType:  req.AuthorType,
-		AuthorID:    req.AuthorID,
-		Version:     1,
-		CreatedAt:   time.Now(),
-	}
-
-	if err := ctrl.DB.Create(&bid).Error; err != nil {
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Description:    req.Description,
+		Status:         models.BidCreated,
+		TenderID:       req.TenderID,
+		AuthorType:     req.AuthorType,
+		AuthorID:       req.AuthorID,
+		Price:          req.Price,
+		OrganizationID: organizationID,
+		Version:        1,
+		CreatedAt:      time.Now(),
+		Lots:           bidLots,
+	}
+
+	if err := db.Create(&bid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create bid"})
+		logError(c, err, "Failed to create bid")
 		return
 	}
 
@@ -88,12 +215,14 @@ func (ctrl BidController) CreateBid(c *gin.Context) {
 }
 
 func (ctrl BidController) GetUserBids(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bids []models.Bid
 	var employee models.Employee
 	var err error
 
 	// Получение параметров запроса
-	limitStr := c.DefaultQuery("limit", "5")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
 	offsetStr := c.DefaultQuery("offset", "0")
 	username := c.Query("username")
 
@@ -106,25 +235,30 @@ func (ctrl BidController) GetUserBids(c *gin.Context) {
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
 		return
 	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
 		return
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
-	query := ctrl.DB.Limit(limit).Offset(offset).Order("name").Where("author_id = ?", employee.ID)
+	query := db.Preload("Lots").Limit(limit).Offset(offset).Order("name").Where("author_id = ?", employee.ID)
 
 	if err = query.Find(&bids).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+		logError(c, err, "Failed to retrieve bids")
 		return
 	}
 
@@ -132,6 +266,8 @@ func (ctrl BidController) GetUserBids(c *gin.Context) {
 }
 
 func (ctrl BidController) GetTenderBids(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bids []models.Bid
 	var tender models.Tender
 	var employee models.Employee
@@ -142,7 +278,7 @@ func (ctrl BidController) GetTenderBids(c *gin.Context) {
 	username := c.Query("username")
 
 	// Получение параметров запроса
-	limitStr := c.DefaultQuery("limit", "5")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
 	offsetStr := c.DefaultQuery("offset", "0")
 
 	// Проверка обязательных параметров
@@ -154,37 +290,72 @@ func (ctrl BidController) GetTenderBids(c *gin.Context) {
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
 		return
 	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
 		return
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации, которая разместила тендер
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Пока предложения запечатаны, организации сообщается только их количество
+	sealed, err := bidsSealed(db, tender)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+		logError(c, err, "Failed to retrieve bids")
 		return
 	}
 
-	query := ctrl.DB.Limit(limit).Offset(offset).Order("name").Where("tender_id = ? AND status = ?", tenderID, models.Published)
+	if sealed {
+		var count int64
+		if err := db.Model(&models.Bid{}).Where("tender_id = ? AND status = ?", tenderID, models.Published).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+			logError(c, err, "Failed to retrieve bids")
+			return
+		}
+
+		c.JSON(http.StatusOK, SealedBidsResponse{Sealed: true, Count: count, Deadline: tender.Deadline})
+		return
+	}
+
+	// Отозванные предложения остаются видны организации вместе с поданными
+	query := db.Preload("Lots").Limit(limit).Offset(offset).Order("name").Where("tender_id = ? AND status IN ?", tenderID, []models.BidStatus{models.BidPublished, models.BidWithdrawn})
 
 	if err = query.Find(&bids).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+		logError(c, err, "Failed to retrieve bids")
+		return
+	}
+
+	// Репутация авторов помогает ответственным лицам при принятии решения
+	if err := withReputation(db, bids); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve reputation"})
+		logError(c, err, "Failed to retrieve reputation")
 		return
 	}
 
@@ -192,6 +363,8 @@ func (ctrl BidController) GetTenderBids(c *gin.Context) {
 }
 
 func (ctrl BidController) GetBidStatus(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -200,14 +373,16 @@ func (ctrl BidController) GetBidStatus(c *gin.Context) {
 	username := c.Query("username")
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -219,8 +394,9 @@ func (ctrl BidController) GetBidStatus(c *gin.Context) {
 			return
 		} else {
 			// Проверка, что пользователь является ответственным лицом в той же организации
-			if err := ctrl.DB.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
+			if err := db.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+				logError(c, err, "User is not authorized")
 				return
 			}
 		}
@@ -236,7 +412,10 @@ func (ctrl BidController) GetBidStatus(c *gin.Context) {
 }
 
 func (ctrl BidController) UpdateBidStatus(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
+	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
 
@@ -251,14 +430,16 @@ func (ctrl BidController) UpdateBidStatus(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -268,8 +449,9 @@ func (ctrl BidController) UpdateBidStatus(c *gin.Context) {
 			// Пользователь является автором предложения
 		} else {
 			// Проверка, что пользователь является ответственным лицом в той же организации
-			if err := ctrl.DB.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
+			if err := db.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+				logError(c, err, "User is not authorized")
 				return
 			}
 		}
@@ -281,6 +463,12 @@ func (ctrl BidController) UpdateBidStatus(c *gin.Context) {
 		}
 	}
 
+	// Отозванное предложение возвращается в тендер только повторной подачей
+	if bid.Status == models.BidWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is withdrawn, use resubmit"})
+		return
+	}
+
 	// Проверка корректности статуса
 
 	var newStatus models.BidStatus
@@ -296,24 +484,73 @@ func (ctrl BidController) UpdateBidStatus(c *gin.Context) {
 		return
 	}
 
+	if newStatus == models.BidPublished {
+		// Проверка существования тендера
+		if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+			logError(c, err, "Tender not found")
+			return
+		}
+
+		// Опубликовать предложение после вскрытия запечатанного тендера нельзя, как и подать новое
+		if opened, err := bidsOpened(db, tender); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tender openings"})
+			logError(c, err, "Failed to retrieve tender openings")
+			return
+		} else if opened {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are already opened"})
+			return
+		}
+	}
+
+	// После первого решения организации статус предложения определяют решения: отклонённое предложение
+	// нельзя опубликовать заново, а рассматриваемое - отменить, как и отозвать
+	var decisionCount int64
+	if err := db.Model(&models.Decision{}).Where("bid_id = ?", bid.ID).Count(&decisionCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check decisions"})
+		logError(c, err, "Failed to check decisions")
+		return
+	}
+
+	if decisionCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid already has a decision"})
+		return
+	}
+
 	// Обновление статуса предложения
+	oldStatus := bid.Status
 	bid.Status = newStatus
 
-	if err := ctrl.DB.Model(&bid).Update("status", newStatus).Error; err != nil {
+	if err := db.Model(&bid).Update("status", newStatus).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update bid status"})
+		logError(c, err, "Failed to update bid status")
 		return
 	}
 
 	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload bid"})
+		logError(c, err, "Failed to reload bid")
 		return
 	}
+
+	if bid.Status == models.BidPublished && oldStatus != models.BidPublished {
+		if err := events.BidPublished(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish bid event", "error", err)
+		}
+		if err := ctrl.Notifications.NewBid(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, bid)
 }
 
 func (ctrl BidController) EditBid(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
+	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
 	var req UpdateBidRequest
@@ -328,14 +565,16 @@ func (ctrl BidController) EditBid(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -345,8 +584,9 @@ func (ctrl BidController) EditBid(c *gin.Context) {
 			// Пользователь является автором предложения
 		} else {
 			// Проверка, что пользователь является ответственным лицом в той же организации
-			if err := ctrl.DB.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
+			if err := db.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+				logError(c, err, "User is not authorized")
 				return
 			}
 		}
@@ -358,9 +598,27 @@ func (ctrl BidController) EditBid(c *gin.Context) {
 		}
 	}
 
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Вскрытые предложения запечатанного тендера видны ответственным лицам, поэтому менять их уже нельзя
+	if opened, err := bidsOpened(db, tender); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tender openings"})
+		logError(c, err, "Failed to retrieve tender openings")
+		return
+	} else if opened {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are already opened"})
+		return
+	}
+
 	// Обновление полей предложения
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
 		return
 	}
 
@@ -376,22 +634,57 @@ func (ctrl BidController) EditBid(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.DB.Model(&bid).Updates(req).Error; err != nil {
+	if req.Status != "" && bid.Status == models.BidWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is withdrawn, use resubmit"})
+		return
+	}
+
+	// Смена статуса после решения организации запрещена так же, как в UpdateBidStatus
+	if req.Status != "" {
+		var decisionCount int64
+		if err := db.Model(&models.Decision{}).Where("bid_id = ?", bid.ID).Count(&decisionCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check decisions"})
+			logError(c, err, "Failed to check decisions")
+			return
+		}
+
+		if decisionCount > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid already has a decision"})
+			return
+		}
+	}
+
+	oldStatus := bid.Status
+	if err := db.Model(&bid).Updates(req).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update bid"})
+		logError(c, err, "Failed to update bid")
 		return
 	}
 
 	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload bid"})
+		logError(c, err, "Failed to reload bid")
 		return
 	}
 
+	if bid.Status == models.BidPublished && oldStatus != models.BidPublished {
+		if err := events.BidPublished(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish bid event", "error", err)
+		}
+		if err := ctrl.Notifications.NewBid(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, bid)
 }
 
 func (ctrl BidController) RollbackBid(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
+	var tender models.Tender
 	var bidHistory models.BidHistory
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -407,14 +700,16 @@ func (ctrl BidController) RollbackBid(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -424,8 +719,9 @@ func (ctrl BidController) RollbackBid(c *gin.Context) {
 			// Пользователь является автором предложения
 		} else {
 			// Проверка, что пользователь является ответственным лицом в той же организации
-			if err := ctrl.DB.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
+			if err := db.Where("user_id = ? AND organization_id = (SELECT organization_id FROM organization_responsibles WHERE user_id = ?)", employee.ID, bid.AuthorID).First(&orgResp).Error; err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+				logError(c, err, "User is not authorized")
 				return
 			}
 		}
@@ -437,34 +733,84 @@ func (ctrl BidController) RollbackBid(c *gin.Context) {
 		}
 	}
 
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Откат меняет предложение так же, как редактирование, поэтому после вскрытия тоже запрещён
+	if opened, err := bidsOpened(db, tender); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tender openings"})
+		logError(c, err, "Failed to retrieve tender openings")
+		return
+	} else if opened {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are already opened"})
+		return
+	}
+
 	// Преобразование версии в int
 	version, err := strconv.Atoi(versionStr)
 	if err != nil || version <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid version parameter"})
+		logError(c, err, "Invalid version parameter")
 		return
 	}
 
 	// Поиск истории предложения по версии
-	if err := ctrl.DB.Where("bid_id = ? AND version = ?", bidID, version).First(&bidHistory).Error; err != nil {
+	if err := db.Where("bid_id = ? AND version = ?", bidID, version).First(&bidHistory).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid version not found"})
+		logError(c, err, "Bid version not found")
 		return
 	}
 
-	// Откат предложения к указанной версии
+	// Откат возвращает и статус версии, поэтому после решения организации он запрещён, как и смена статуса
+	var decisionCount int64
+	if err := db.Model(&models.Decision{}).Where("bid_id = ?", bid.ID).Count(&decisionCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check decisions"})
+		logError(c, err, "Failed to check decisions")
+		return
+	}
+
+	if decisionCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid already has a decision"})
+		return
+	}
+
+	// Откат не меняет статус отзыва: отзыв и повторная подача выполняются только явно, чтобы сохранялась их история
+	if bid.Status == models.BidWithdrawn || bidHistory.Status == models.BidWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Withdrawn bid versions cannot be rolled back"})
+		return
+	}
+
+	// Откат предложения к указанной версии. Цена не откатывается: в аукционе её можно только понижать
+	oldStatus := bid.Status
 	bid.Name = bidHistory.Name
 	bid.Description = bidHistory.Description
 	bid.Status = bidHistory.Status
 
-	if err := ctrl.DB.Model(&bid).Updates(bid).Error; err != nil {
+	if err := db.Model(&bid).Updates(bid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to rollback bid"})
+		logError(c, err, "Failed to rollback bid")
 		return
 	}
 
 	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload bid"})
+		logError(c, err, "Failed to reload bid")
 		return
 	}
 
+	if bid.Status == models.BidPublished && oldStatus != models.BidPublished {
+		if err := events.BidPublished(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish bid event", "error", err)
+		}
+		if err := ctrl.Notifications.NewBid(db, bid); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, bid)
 }
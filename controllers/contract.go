@@ -0,0 +1,490 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/documents"
+	"myapp/models"
+	"myapp/workflow"
+)
+
+type ContractController struct {
+	DB       *gorm.DB
+	Renderer *documents.ContractRenderer
+	Quorum   config.Quorum
+}
+
+func (ctrl ContractController) CreateContract(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var bid models.Bid
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+	bidID := c.Query("bidId")
+	lotID := c.Query("lotId")
+
+	// Проверка обязательных параметров
+	if username == "" || bidID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Договор составляется только по закрытому тендеру
+	if tender.Status != models.Closed {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not closed"})
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ? AND tender_id = ?", bidID, tender.ID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	if bid.Status != models.BidPublished {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not published"})
+		return
+	}
+
+	contract := models.Contract{
+		ID:                     uuid.New(),
+		TenderID:               tender.ID,
+		TenderVersion:          tender.Version,
+		BidID:                  bid.ID,
+		BidVersion:             bid.Version,
+		OrganizationID:         tender.OrganizationID,
+		SupplierType:           bid.AuthorType,
+		SupplierID:             bid.AuthorID,
+		SupplierOrganizationID: bid.OrganizationID,
+		TenderName:             tender.Name,
+		TenderDescription:      tender.Description,
+		ServiceType:            tender.ServiceType,
+		BidName:                bid.Name,
+		BidDescription:         bid.Description,
+		Price:                  bid.Price,
+		Status:                 models.ContractDraft,
+	}
+
+	var lotCount int64
+	if err := db.Model(&models.TenderLot{}).Where("tender_id = ?", tender.ID).Count(&lotCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve lots"})
+		logError(c, err, "Failed to retrieve lots")
+		return
+	}
+
+	existing := db.Model(&models.Contract{}).Where("bid_id = ?", bid.ID)
+
+	if lotCount > 0 {
+		var lot models.TenderLot
+		var bidLot models.BidLot
+
+		// По многолотовому тендеру договор заключается на лот, присуждённый предложению
+		if lotID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+			return
+		}
+
+		if err := db.Where("id = ? AND tender_id = ?", lotID, tender.ID).First(&lot).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"reason": "Lot not found"})
+			logError(c, err, "Lot not found")
+			return
+		}
+
+		if lot.WinnerBidID == nil || *lot.WinnerBidID != bid.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Lot is not awarded to this bid"})
+			return
+		}
+
+		if err := db.Where("bid_id = ? AND lot_id = ?", bid.ID, lot.ID).First(&bidLot).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bid lot"})
+			logError(c, err, "Failed to retrieve bid lot")
+			return
+		}
+
+		contract.LotID = &lot.ID
+		contract.LotName = lot.Name
+		contract.Price = &bidLot.Price
+		existing = existing.Where("lot_id = ?", lot.ID)
+	} else {
+		if lotID != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender has no lots"})
+			return
+		}
+
+		// Предложение должно было набрать кворум одобрений
+		var approvedCount int64
+		if err := db.Model(&models.Decision{}).Where("bid_id = ? AND decision_type = ?", bid.ID, models.Approved).Count(&approvedCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve decisions"})
+			logError(c, err, "Failed to retrieve decisions")
+			return
+		}
+
+		required, err := workflow.ApprovalQuorum(db, tender, ctrl.Quorum)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve responsibles"})
+			logError(c, err, "Failed to retrieve responsibles")
+			return
+		}
+
+		if approvedCount == 0 || approvedCount < required {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not approved"})
+			return
+		}
+	}
+
+	// По одному предложению (лоту) составляется один договор
+	var count int64
+	if err := existing.Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check contracts"})
+		logError(c, err, "Failed to check contracts")
+		return
+	}
+
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"reason": "Contract already exists"})
+		return
+	}
+
+	// Параллельно созданный договор отсекают уникальные индексы
+	if err := db.Create(&contract).Error; err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"reason": "Contract already exists"})
+			logError(c, err, "Contract already exists")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create contract"})
+		logError(c, err, "Failed to create contract")
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+func (ctrl ContractController) GetTenderContracts(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+	var contracts []models.Contract
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	if err := db.Order("created_at").Where("tender_id = ?", tender.ID).Find(&contracts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve contracts"})
+		logError(c, err, "Failed to retrieve contracts")
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts)
+}
+
+func (ctrl ContractController) GetContract(c *gin.Context) {
+	contract, _, _, ok := ctrl.loadContract(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+func (ctrl ContractController) SignAsBuyer(c *gin.Context) {
+	contract, employee, party, ok := ctrl.loadContract(c)
+	if !ok {
+		return
+	}
+
+	if !party.buyer {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized to sign for the buyer"})
+		return
+	}
+
+	if contract.Status != models.ContractDraft && contract.Status != models.ContractSignedBySupplier {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Contract cannot be signed by the buyer"})
+		return
+	}
+
+	prevStatus := contract.Status
+	now := time.Now()
+	contract.BuyerSignedBy = &employee.ID
+	contract.BuyerSignedAt = &now
+	contract.Status = models.ContractSignedByBuyer
+	if contract.SupplierSignedAt != nil {
+		contract.Status = models.ContractActive
+	}
+
+	ctrl.saveContract(c, contract, prevStatus)
+}
+
+func (ctrl ContractController) SignAsSupplier(c *gin.Context) {
+	contract, employee, party, ok := ctrl.loadContract(c)
+	if !ok {
+		return
+	}
+
+	if !party.supplier {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized to sign for the supplier"})
+		return
+	}
+
+	if contract.Status != models.ContractDraft && contract.Status != models.ContractSignedByBuyer {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Contract cannot be signed by the supplier"})
+		return
+	}
+
+	prevStatus := contract.Status
+	now := time.Now()
+	contract.SupplierSignedBy = &employee.ID
+	contract.SupplierSignedAt = &now
+	contract.Status = models.ContractSignedBySupplier
+	if contract.BuyerSignedAt != nil {
+		contract.Status = models.ContractActive
+	}
+
+	ctrl.saveContract(c, contract, prevStatus)
+}
+
+func (ctrl ContractController) CompleteContract(c *gin.Context) {
+	contract, _, party, ok := ctrl.loadContract(c)
+	if !ok {
+		return
+	}
+
+	// Исполнение договора подтверждает заказчик
+	if !party.buyer {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized to complete the contract"})
+		return
+	}
+
+	if contract.Status != models.ContractActive {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Contract is not active"})
+		return
+	}
+
+	prevStatus := contract.Status
+	now := time.Now()
+	contract.CompletedAt = &now
+	contract.Status = models.ContractCompleted
+
+	ctrl.saveContract(c, contract, prevStatus)
+}
+
+func (ctrl ContractController) DownloadContractPDF(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var buyer models.Organization
+
+	contract, _, _, ok := ctrl.loadContract(c)
+	if !ok {
+		return
+	}
+
+	if ctrl.Renderer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"reason": "Contract rendering is not configured"})
+		return
+	}
+
+	if err := db.Where("id = ?", contract.OrganizationID).First(&buyer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve organization"})
+		logError(c, err, "Failed to retrieve organization")
+		return
+	}
+
+	data := documents.ContractData{
+		Contract:       contract,
+		Buyer:          buyer,
+		Supplier:       ctrl.supplierName(db, contract),
+		BuyerSigner:    ctrl.employeeName(db, contract.BuyerSignedBy),
+		SupplierSigner: ctrl.employeeName(db, contract.SupplierSignedBy),
+	}
+
+	pdf, err := ctrl.Renderer.Render(data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to render contract"})
+		logError(c, err, "Failed to render contract")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="contract-%s.pdf"`, contract.ID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// contractParty - стороны договора, от имени которых может действовать пользователь
+type contractParty struct {
+	buyer    bool
+	supplier bool
+}
+
+// loadContract загружает договор и проверяет, что пользователь представляет одну из его сторон.
+// При ошибке отправляет ответ и возвращает false
+func (ctrl ContractController) loadContract(c *gin.Context) (models.Contract, models.Employee, contractParty, bool) {
+	db := requestDB(c, ctrl.DB)
+
+	var contract models.Contract
+	var employee models.Employee
+	var party contractParty
+	var err error
+
+	contractID := c.Param("contractId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return contract, employee, party, false
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return contract, employee, party, false
+	}
+
+	// Проверка существования договора
+	if err := db.Where("id = ?", contractID).First(&contract).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Contract not found"})
+		logError(c, err, "Contract not found")
+		return contract, employee, party, false
+	}
+
+	party.buyer, err = isResponsible(db, employee.ID, contract.OrganizationID)
+	if err == nil {
+		party.supplier, err = isSupplier(db, contract, employee)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check authorization"})
+		logError(c, err, "Failed to check authorization")
+		return contract, employee, party, false
+	}
+
+	if !party.buyer && !party.supplier {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this contract"})
+		return contract, employee, party, false
+	}
+
+	return contract, employee, party, true
+}
+
+// saveContract сохраняет переход договора в новый статус. Условие на прежний статус не даёт сторонам,
+// подписывающим договор одновременно, затереть подписи друг друга
+func (ctrl ContractController) saveContract(c *gin.Context, contract models.Contract, prevStatus models.ContractStatus) {
+	db := requestDB(c, ctrl.DB)
+
+	result := db.Model(&contract).Where("status = ?", prevStatus).
+		Select("status", "buyer_signed_by", "buyer_signed_at", "supplier_signed_by", "supplier_signed_at", "completed_at").
+		Updates(&contract)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update contract"})
+		logError(c, result.Error, "Failed to update contract")
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"reason": "Contract was changed by another request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+// isSupplier проверяет, что пользователь представляет поставщика: автор предложения или ответственное лицо
+// организации, закреплённой за договором. Договоры без закреплённой организации проверяются так же,
+// как владелец предложения
+func isSupplier(db *gorm.DB, contract models.Contract, employee models.Employee) (bool, error) {
+	if contract.SupplierID == employee.ID {
+		return true, nil
+	}
+
+	if contract.SupplierOrganizationID != nil {
+		return isResponsible(db, employee.ID, *contract.SupplierOrganizationID)
+	}
+
+	return isBidOwner(db, models.Bid{AuthorType: contract.SupplierType, AuthorID: contract.SupplierID}, employee)
+}
+
+// supplierName возвращает наименование поставщика: закреплённую за договором организацию, иначе имя автора
+func (ctrl ContractController) supplierName(db *gorm.DB, contract models.Contract) string {
+	if contract.SupplierOrganizationID != nil {
+		var organization models.Organization
+		if err := db.Where("id = ?", *contract.SupplierOrganizationID).First(&organization).Error; err == nil {
+			return fmt.Sprintf("%s (%s)", organization.Name, organization.Type)
+		}
+	}
+
+	return ctrl.employeeName(db, &contract.SupplierID)
+}
+
+func (ctrl ContractController) employeeName(db *gorm.DB, id *uuid.UUID) string {
+	var employee models.Employee
+	if id == nil || db.Where("id = ?", *id).First(&employee).Error != nil {
+		return ""
+	}
+
+	if name := strings.TrimSpace(employee.FirstName + " " + employee.LastName); name != "" {
+		return name
+	}
+
+	return employee.Username
+}
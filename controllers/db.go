@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+// requestDB привязывает запросы к базе данных к контексту HTTP-запроса: они отменяются вместе с ним
+// и попадают в его трассировку. Каждый обработчик начинается с получения такого подключения
+func requestDB(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(c.Request.Context())
+}
+
+// isUniqueViolation сообщает, что запись не создана из-за уникального индекса. Предварительные проверки
+// существования не защищают от параллельных запросов, поэтому окончательное решение принимает индекс
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
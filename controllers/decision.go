@@ -1,28 +1,35 @@
 package controllers
 
 import (
-	"math"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"myapp/config"
 	"myapp/models"
+	"myapp/notify"
+	"myapp/workflow"
 )
 
 type DecisionController struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Notifications *notify.Service
+	Quorum        config.Quorum
 }
 
 func (ctrl DecisionController) SubmitDecision(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
-	var decision models.Decision
 
 	bidID := c.Param("bidID")
 	username := c.Query("username")
 	decisionType := c.Query("decision")
+	lotID := c.Query("lotId")
 
 	// Проверка обязательных параметров
 	if username == "" || decisionType == "" {
@@ -31,14 +38,16 @@ func (ctrl DecisionController) SubmitDecision(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -49,8 +58,9 @@ func (ctrl DecisionController) SubmitDecision(c *gin.Context) {
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
@@ -61,8 +71,16 @@ func (ctrl DecisionController) SubmitDecision(c *gin.Context) {
 	}
 
 	// Проверка авторизации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+		logError(c, err, "User is not authorized")
+		return
+	}
+
+	// Решение по запечатанному предложению принимается только после вскрытия
+	if sealed, err := bidsSealed(db, tender); err != nil || sealed {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "Tender bids are sealed"})
+		logError(c, err, "Tender bids are sealed")
 		return
 	}
 
@@ -77,47 +95,103 @@ func (ctrl DecisionController) SubmitDecision(c *gin.Context) {
 		return
 	}
 
-	// Создание решения
-	decision = models.Decision{
+	// Для многолотовых тендеров решение принимается по конкретному лоту
+	var lotCount int64
+	if err := db.Model(&models.TenderLot{}).Where("tender_id = ?", tender.ID).Count(&lotCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve lots"})
+		logError(c, err, "Failed to retrieve lots")
+		return
+	}
+
+	if lotCount > 0 {
+		ctrl.submitLotDecision(c, bid, tender, employee, models.DecisionType(decisionType))
+		return
+	}
+
+	if lotID != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender has no lots"})
+		return
+	}
+
+	ctrl.submitDecision(c, db, tender, bid, models.Decision{
 		BidID:        bid.ID,
 		AuthorID:     employee.ID,
 		DecisionType: models.DecisionType(decisionType),
+	})
+}
+
+// submitLotDecision принимает решение по предложению в рамках одного лота. Отклонение снимает предложение
+// только с этого лота, кворум одобрений присуждает лот предложению, а тендер закрывается,
+// когда присуждены все его лоты
+func (ctrl DecisionController) submitLotDecision(c *gin.Context, bid models.Bid, tender models.Tender, employee models.Employee, decisionType models.DecisionType) {
+	db := requestDB(c, ctrl.DB)
+
+	var lot models.TenderLot
+	var bidLot models.BidLot
+
+	lotID := c.Query("lotId")
+
+	// Проверка обязательных параметров
+	if lotID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
+		return
 	}
 
-	if err := ctrl.DB.Create(&decision).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to submit decision"})
+	// Проверка существования лота
+	if err := db.Where("id = ? AND tender_id = ?", lotID, tender.ID).First(&lot).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Lot not found"})
+		logError(c, err, "Lot not found")
 		return
 	}
 
-	// Обновление статуса предложения, если решение отклонено
-	if decision.DecisionType == models.Rejected {
-		bid.Status = models.BidCanceled
-		if err := ctrl.DB.Model(&bid).Update("status", models.BidCanceled).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update bid status"})
-			return
-		}
+	if lot.Status != models.LotOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Lot is already awarded"})
+		return
 	}
 
-	// Проверка кворума для одобренных решений
-	var approvedCount int64
-	ctrl.DB.Model(&models.Decision{}).Where("bid_id = ? AND decision_type = ?", bid.ID, models.Approved).Count(&approvedCount)
+	// Проверка, что предложение подано на этот лот
+	if err := db.Where("bid_id = ? AND lot_id = ?", bid.ID, lot.ID).First(&bidLot).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid does not target this lot"})
+		logError(c, err, "Bid does not target this lot")
+		return
+	}
 
-	var responsibleCount int64
-	ctrl.DB.Model(&models.OrganizationResponsible{}).Where("organization_id = ?", tender.OrganizationID).Count(&responsibleCount)
+	if bidLot.Status == models.BidLotRejected {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is rejected for this lot"})
+		return
+	}
 
-	quorum := int64(math.Min(3, float64(responsibleCount)))
+	ctrl.submitDecision(c, db, tender, bid, models.Decision{
+		BidID:        bid.ID,
+		LotID:        &lot.ID,
+		AuthorID:     employee.ID,
+		DecisionType: decisionType,
+	})
+}
 
-	if approvedCount >= quorum {
-		tender.Status = models.Closed
-		if err := ctrl.DB.Model(&tender).Update("status", models.Closed).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update tender status"})
-			return
-		}
+// submitDecision сохраняет решение общим переходом и отвечает перечитанным предложением
+func (ctrl DecisionController) submitDecision(c *gin.Context, db *gorm.DB, tender models.Tender, bid models.Bid, decision models.Decision) {
+	_, bid, err := workflow.SubmitDecision(db, ctrl.Notifications, ctrl.Quorum, &tender, bid, decision)
+	if errors.Is(err, workflow.ErrNotPublished) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+		return
 	}
-
-	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload bid"})
+	if errors.Is(err, workflow.ErrBidNotPublished) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not published"})
+		return
+	}
+	if errors.Is(err, workflow.ErrLotAwarded) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Lot is already awarded"})
+		return
+	}
+	if err != nil {
+		reason := "Failed to submit decision"
+		var stepErr *workflow.Error
+		if errors.As(err, &stepErr) {
+			reason = stepErr.Reason
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": reason})
+		logError(c, err, reason)
 		return
 	}
 
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+type InvitationController struct {
+	DB *gorm.DB
+}
+
+type CreateInvitationRequest struct {
+	EmployeeUsername string     `json:"employeeUsername,omitempty"`
+	OrganizationID   *uuid.UUID `json:"organizationId,omitempty"`
+}
+
+func (ctrl InvitationController) CreateInvitation(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var req CreateInvitationRequest
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Приглашается либо сотрудник, либо организация
+	if (req.EmployeeUsername == "") == (req.OrganizationID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Either employeeUsername or organizationId must be specified"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	if tender.Visibility != models.VisibilityInviteOnly {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not invite-only"})
+		return
+	}
+
+	invitation := models.TenderInvitation{
+		ID:        uuid.New(),
+		TenderID:  tender.ID,
+		InvitedBy: employee.ID,
+	}
+
+	query := db.Model(&models.TenderInvitation{}).Where("tender_id = ?", tender.ID)
+
+	if req.EmployeeUsername != "" {
+		var invitee models.Employee
+
+		// Проверка существования приглашаемого сотрудника
+		if err := db.Where("username = ?", req.EmployeeUsername).First(&invitee).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"reason": "Invited user does not exist"})
+			logError(c, err, "Invited user does not exist")
+			return
+		}
+
+		invitation.EmployeeID = &invitee.ID
+		query = query.Where("employee_id = ?", invitee.ID)
+	} else {
+		var organization models.Organization
+
+		// Проверка существования приглашаемой организации
+		if err := db.Where("id = ?", *req.OrganizationID).First(&organization).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"reason": "Invited organization does not exist"})
+			logError(c, err, "Invited organization does not exist")
+			return
+		}
+
+		invitation.OrganizationID = &organization.ID
+		query = query.Where("organization_id = ?", organization.ID)
+	}
+
+	// Повторное приглашение не создаётся
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check invitations"})
+		logError(c, err, "Failed to check invitations")
+		return
+	}
+
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"reason": "Invitation already exists"})
+		return
+	}
+
+	// Одновременное повторное приглашение отсекают уникальные индексы
+	if err := db.Omit("Tender").Create(&invitation).Error; err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"reason": "Invitation already exists"})
+			logError(c, err, "Invitation already exists")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create invitation"})
+		logError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+func (ctrl InvitationController) GetInvitations(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+	var invitations []models.TenderInvitation
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Список приглашённых видят только ответственные лица организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	if err := db.Order("created_at").Where("tender_id = ?", tender.ID).Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve invitations"})
+		logError(c, err, "Failed to retrieve invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (ctrl InvitationController) DeleteInvitation(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+	var invitation models.TenderInvitation
+
+	tenderID := c.Param("tenderId")
+	invitationID := c.Param("invitationId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Проверка существования приглашения
+	if err := db.Where("id = ? AND tender_id = ?", invitationID, tender.ID).First(&invitation).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Invitation not found"})
+		logError(c, err, "Invitation not found")
+		return
+	}
+
+	// Уже поданные предложения отозванного участника остаются в силе
+	if err := db.Delete(&invitation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to delete invitation"})
+		logError(c, err, "Failed to delete invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// logError записывает в журнал ошибку, из-за которой запрос завершился отказом, вместе с пользователем
+// и идентификаторами сущностей из пути и параметров запроса. Вызывается после отправки ответа, чтобы учесть его статус.
+// Ненайденные записи и некорректные запросы - штатные ответы API, поэтому пишутся с уровнем Debug
+func logError(c *gin.Context, err error, reason string) {
+	if err == nil {
+		return
+	}
+
+	status := c.Writer.Status()
+
+	level := slog.LevelWarn
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status == http.StatusBadRequest, errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelDebug
+	}
+
+	attrs := []slog.Attr{
+		slog.String("reason", reason),
+		slog.String("error", err.Error()),
+		slog.Int("status", status),
+		slog.String("route", c.FullPath()),
+	}
+	if username := c.Query("username"); username != "" {
+		attrs = append(attrs, slog.String("username", username))
+	}
+	for _, param := range c.Params {
+		attrs = append(attrs, slog.String(param.Key, param.Value))
+	}
+	for key, values := range c.Request.URL.Query() {
+		if strings.HasSuffix(key, "Id") && len(values) > 0 {
+			attrs = append(attrs, slog.String(key, values[0]))
+		}
+	}
+
+	slog.LogAttrs(c.Request.Context(), level, "Request failed", attrs...)
+}
@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/models"
+	"myapp/notify"
+)
+
+type NotificationController struct {
+	DB         *gorm.DB
+	Pagination config.Pagination
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Email        *string `json:"email,omitempty"`
+	Locale       *string `json:"locale,omitempty"`
+	EmailEnabled *bool   `json:"emailEnabled,omitempty"`
+	BidDecision  *bool   `json:"bidDecision,omitempty"`
+	BidFeedback  *bool   `json:"bidFeedback,omitempty"`
+	TenderClosed *bool   `json:"tenderClosed,omitempty"`
+	NewBid       *bool   `json:"newBid,omitempty"`
+	ReviewReply  *bool   `json:"reviewReply,omitempty"`
+}
+
+type NotificationPreferencesResponse struct {
+	Email string `json:"email"`
+	models.NotificationPreference
+}
+
+func (ctrl NotificationController) GetPreferences(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	pref, err := ctrl.loadPreference(db, employee.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve notification preferences"})
+		logError(c, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferencesResponse{Email: employee.Email, NotificationPreference: pref})
+}
+
+func (ctrl NotificationController) UpdatePreferences(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+	var req UpdateNotificationPreferencesRequest
+
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка адреса электронной почты
+	if req.Email != nil && *req.Email != "" {
+		if _, err := mail.ParseAddress(*req.Email); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid email"})
+			logError(c, err, "Invalid email")
+			return
+		}
+	}
+
+	// Проверка языка уведомлений
+	if req.Locale != nil && !notify.SupportedLocale(*req.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid locale"})
+		return
+	}
+
+	pref, err := ctrl.loadPreference(db, employee.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve notification preferences"})
+		logError(c, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	if req.Locale != nil {
+		pref.Locale = *req.Locale
+	}
+	if req.EmailEnabled != nil {
+		pref.EmailEnabled = *req.EmailEnabled
+	}
+	if req.BidDecision != nil {
+		pref.BidDecision = *req.BidDecision
+	}
+	if req.BidFeedback != nil {
+		pref.BidFeedback = *req.BidFeedback
+	}
+	if req.TenderClosed != nil {
+		pref.TenderClosed = *req.TenderClosed
+	}
+	if req.NewBid != nil {
+		pref.NewBid = *req.NewBid
+	}
+	if req.ReviewReply != nil {
+		pref.ReviewReply = *req.ReviewReply
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if req.Email != nil {
+			if err := tx.Model(&employee).Update("email", *req.Email).Error; err != nil {
+				return err
+			}
+			employee.Email = *req.Email
+		}
+
+		// Все поля сохраняются явно, иначе gorm пропустит значения false
+		if pref.ID == uuid.Nil {
+			pref.ID = uuid.New()
+			return tx.Select("*").Omit("User").Create(&pref).Error
+		}
+		return tx.Select("*").Omit("User").Updates(&pref).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update notification preferences"})
+		logError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferencesResponse{Email: employee.Email, NotificationPreference: pref})
+}
+
+// loadPreference возвращает сохранённые настройки пользователя или настройки по умолчанию
+func (ctrl NotificationController) loadPreference(db *gorm.DB, userID uuid.UUID) (models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+
+	err := db.Where("user_id = ?", userID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+
+	return pref, err
+}
+
+func (ctrl NotificationController) GetNotifications(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var notifications []models.Notification
+	var employee models.Employee
+	var err error
+
+	// Получение параметров запроса
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
+	offsetStr := c.DefaultQuery("offset", "0")
+	unreadStr := c.DefaultQuery("unread", "false")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
+		return
+	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
+		return
+	}
+
+	unread, err := strconv.ParseBool(unreadStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid unread parameter"})
+		logError(c, err, "Invalid unread parameter")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	query := db.Limit(limit).Offset(offset).Order("created_at DESC").Where("user_id = ?", employee.ID)
+
+	if unread {
+		query = query.Where("read = ?", false)
+	}
+
+	if err = query.Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve notifications"})
+		logError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (ctrl NotificationController) GetUnreadCount(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+	var count int64
+
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	if err := db.Model(&models.Notification{}).Where("user_id = ? AND read = ?", employee.ID, false).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to count notifications"})
+		logError(c, err, "Failed to count notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+func (ctrl NotificationController) MarkRead(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var notification models.Notification
+	var employee models.Employee
+
+	notificationID := c.Param("notificationId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования уведомления
+	if err := db.Where("id = ?", notificationID).First(&notification).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Notification not found"})
+		logError(c, err, "Notification not found")
+		return
+	}
+
+	// Проверка, что уведомление адресовано пользователю
+	if notification.UserID != employee.ID {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this notification"})
+		return
+	}
+
+	if !notification.Read {
+		now := time.Now()
+		if err := db.Model(&notification).Updates(map[string]any{"read": true, "read_at": now}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update notification"})
+			logError(c, err, "Failed to update notification")
+			return
+		}
+		notification.Read = true
+		notification.ReadAt = &now
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func (ctrl NotificationController) MarkAllRead(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	result := db.Model(&models.Notification{}).Where("user_id = ? AND read = ?", employee.ID, false).Updates(map[string]any{"read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update notifications"})
+		logError(c, result.Error, "Failed to update notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": result.RowsAffected})
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/models"
+)
+
+type QuestionController struct {
+	DB         *gorm.DB
+	Pagination config.Pagination
+}
+
+type CreateQuestionRequest struct {
+	Question string `json:"question" binding:"required,max=1000"`
+}
+
+type AnswerQuestionRequest struct {
+	Answer string `json:"answer" binding:"required,max=2000"`
+	Public bool   `json:"public"`
+}
+
+func (ctrl QuestionController) CreateQuestion(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var req CreateQuestionRequest
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Вопросы принимаются только по опубликованным тендерам
+	if tender.Status != models.Published {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "Tender is not published"})
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	question := models.TenderQuestion{
+		ID:       uuid.New(),
+		TenderID: tender.ID,
+		AuthorID: employee.ID,
+		Question: req.Question,
+	}
+
+	if err := db.Omit("Tender").Create(&question).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create question"})
+		logError(c, err, "Failed to create question")
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (ctrl QuestionController) AnswerQuestion(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var question models.TenderQuestion
+	var req AnswerQuestionRequest
+
+	tenderID := c.Param("tenderId")
+	questionID := c.Param("questionId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if ok, err := isResponsible(db, employee.ID, tender.OrganizationID); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Проверка существования вопроса
+	if err := db.Where("id = ? AND tender_id = ?", questionID, tender.ID).First(&question).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Question not found"})
+		logError(c, err, "Question not found")
+		return
+	}
+
+	// Разъяснение относится к версии тендера, действующей на момент ответа
+	now := time.Now()
+	question.Answer = &req.Answer
+	question.AnsweredBy = &employee.ID
+	question.AnsweredAt = &now
+	question.Public = req.Public
+	question.TenderVersion = &tender.Version
+
+	if err := db.Model(&question).Select("answer", "answered_by", "answered_at", "public", "tender_version").Updates(question).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to answer question"})
+		logError(c, err, "Failed to answer question")
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (ctrl QuestionController) GetQuestions(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var questions []models.TenderQuestion
+	var err error
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Получение параметров запроса
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
+		return
+	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	responsible, err := isResponsible(db, employee.ID, tender.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check authorization"})
+		logError(c, err, "Failed to check authorization")
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	query := db.Limit(limit).Offset(offset).Order("created_at").Where("tender_id = ?", tender.ID)
+
+	// Ответственные лица видят все вопросы, остальные - публичные разъяснения и свои вопросы
+	if !responsible {
+		query = query.Where("(public = ? AND answer IS NOT NULL) OR author_id = ?", true, employee.ID)
+	}
+
+	if err = query.Find(&questions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve questions"})
+		logError(c, err, "Failed to retrieve questions")
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+type reputationRow struct {
+	Key           uuid.UUID
+	Reviews       int64
+	Quality       float64
+	Timeliness    float64
+	Communication float64
+}
+
+// withReputation заполняет репутацию авторов предложений. Для предложений от организации оценки
+// собираются по всем предложениям организации, для личных - по предложениям автора.
+// Учитываются только отзывы с оценками
+func withReputation(db *gorm.DB, bids []models.Bid) error {
+	var userIDs, orgIDs []uuid.UUID
+	for _, bid := range bids {
+		if bid.AuthorType == models.AuthorOrganization {
+			if bid.OrganizationID != nil {
+				orgIDs = append(orgIDs, *bid.OrganizationID)
+			}
+		} else {
+			userIDs = append(userIDs, bid.AuthorID)
+		}
+	}
+
+	byUser := make(map[uuid.UUID]*models.Reputation)
+	if len(userIDs) > 0 {
+		var rows []reputationRow
+		err := db.Table("reviews r").
+			Select("b.author_id AS key, COUNT(r.id) AS reviews, AVG(r.quality) AS quality, AVG(r.timeliness) AS timeliness, AVG(r.communication) AS communication").
+			Joins("JOIN bids b ON b.id = r.bid_id").
+			Where("b.author_type = ? AND b.author_id IN ? AND r.quality IS NOT NULL", models.AuthorUser, userIDs).
+			Group("b.author_id").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			byUser[row.Key] = row.reputation()
+		}
+	}
+
+	// Отзывы относятся к организации, от имени которой подано предложение, а не к текущим организациям его автора
+	byOrg := make(map[uuid.UUID]*models.Reputation)
+	if len(orgIDs) > 0 {
+		var rows []reputationRow
+		err := db.Table("reviews r").
+			Select("b.organization_id AS key, COUNT(r.id) AS reviews, AVG(r.quality) AS quality, AVG(r.timeliness) AS timeliness, AVG(r.communication) AS communication").
+			Joins("JOIN bids b ON b.id = r.bid_id").
+			Where("b.author_type = ? AND b.organization_id IN ? AND r.quality IS NOT NULL", models.AuthorOrganization, orgIDs).
+			Group("b.organization_id").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			byOrg[row.Key] = row.reputation()
+		}
+	}
+
+	for i := range bids {
+		if bids[i].AuthorType == models.AuthorOrganization {
+			if bids[i].OrganizationID != nil {
+				bids[i].Reputation = byOrg[*bids[i].OrganizationID]
+			}
+		} else {
+			bids[i].Reputation = byUser[bids[i].AuthorID]
+		}
+	}
+
+	return nil
+}
+
+func (row reputationRow) reputation() *models.Reputation {
+	return &models.Reputation{
+		Reviews:       row.Reviews,
+		Quality:       row.Quality,
+		Timeliness:    row.Timeliness,
+		Communication: row.Communication,
+		Score:         (row.Quality + row.Timeliness + row.Communication) / 3,
+	}
+}
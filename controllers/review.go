@@ -1,50 +1,110 @@
 package controllers
 
 import (
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/events"
 	"myapp/models"
+	"myapp/notify"
 )
 
 type ReviewController struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Notifications *notify.Service
+	Pagination    config.Pagination
+}
+
+type SubmitReviewRequest struct {
+	Description   string `json:"description" binding:"required,max=1000"`
+	Quality       int    `json:"quality" binding:"required,min=1,max=5"`
+	Timeliness    int    `json:"timeliness" binding:"required,min=1,max=5"`
+	Communication int    `json:"communication" binding:"required,min=1,max=5"`
+}
+
+type UpdateReviewRequest struct {
+	Description   *string `json:"description,omitempty" binding:"omitempty,min=1,max=1000"`
+	Quality       *int    `json:"quality,omitempty" binding:"omitempty,min=1,max=5"`
+	Timeliness    *int    `json:"timeliness,omitempty" binding:"omitempty,min=1,max=5"`
+	Communication *int    `json:"communication,omitempty" binding:"omitempty,min=1,max=5"`
+}
+
+type ReplyReviewRequest struct {
+	Text string `json:"text" binding:"required,max=1000"`
 }
 
 type ReviewResponse struct {
-	ID        string `json:"id"`
-	Feedback  string `json:"description"`
-	CreatedAt string `json:"createdAt"`
+	ID            string              `json:"id"`
+	BidID         string              `json:"bidId"`
+	ReviewerID    string              `json:"reviewerId"`
+	BidAuthorID   string              `json:"bidAuthorId"`
+	Feedback      string              `json:"description"`
+	Quality       *int                `json:"quality,omitempty"`
+	Timeliness    *int                `json:"timeliness,omitempty"`
+	Communication *int                `json:"communication,omitempty"`
+	Reply         *models.ReviewReply `json:"reply,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+func newReviewResponse(review models.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:            review.ID.String(),
+		BidID:         review.BidID.String(),
+		ReviewerID:    review.ReviewerID.String(),
+		BidAuthorID:   review.BidAuthorID.String(),
+		Feedback:      review.Description,
+		Quality:       review.Quality,
+		Timeliness:    review.Timeliness,
+		Communication: review.Communication,
+		Reply:         review.Reply,
+		CreatedAt:     review.CreatedAt,
+		UpdatedAt:     review.UpdatedAt,
+	}
 }
 
 func (ctrl ReviewController) SubmitFeedback(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var bid models.Bid
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
 	var review models.Review
+	var req SubmitReviewRequest
 
 	bidID := c.Param("bidID")
 	username := c.Query("username")
-	bidFeedback := c.Query("bidFeedback")
 
 	// Проверка обязательных параметров
-	if username == "" || bidFeedback == "" {
+	if username == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
 		return
 	}
 
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования предложения
-	if err := ctrl.DB.Where("id = ?", bidID).First(&bid).Error; err != nil {
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
 		return
 	}
 
@@ -55,102 +115,440 @@ func (ctrl ReviewController) SubmitFeedback(c *gin.Context) {
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка авторизации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+		logError(c, err, "User is not authorized")
+		return
+	}
+
+	// Отзыв на запечатанное предложение можно оставить только после вскрытия
+	if sealed, err := bidsSealed(db, tender); err != nil || sealed {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "Tender bids are sealed"})
+		logError(c, err, "Tender bids are sealed")
 		return
 	}
 
 	// Создание отзыва
 	review = models.Review{
-		BidID:       bid.ID,
-		BidAuthorID: employee.ID,
-		Description: bidFeedback,
+		BidID:         bid.ID,
+		ReviewerID:    employee.ID,
+		BidAuthorID:   bid.AuthorID,
+		Description:   req.Description,
+		Quality:       &req.Quality,
+		Timeliness:    &req.Timeliness,
+		Communication: &req.Communication,
 	}
 
-	if err := ctrl.DB.Create(&review).Error; err != nil {
+	if err := db.Create(&review).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to submit feedback"})
+		logError(c, err, "Failed to submit feedback")
 		return
 	}
 
+	if err := events.FeedbackSubmitted(db, review, bid, tender); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to publish feedback event", "error", err)
+	}
+	if err := ctrl.Notifications.BidFeedback(db, bid, tender, review); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+	}
+
 	c.JSON(http.StatusOK, bid)
 }
 
+// GetReviews возвращает отзывы в контексте тендера, ответственным лицом которого является запрашивающий.
+// scope=tender (по умолчанию) - отзывы на предложения этого тендера, при указании authorUsername - только на
+// предложения автора. scope=author - история отзывов автора по всем тендерам, если он подавал предложение
+// на этот тендер. Отзывы других организаций по закрытым тендерам в историю не попадают
 func (ctrl ReviewController) GetReviews(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
+	var author models.Employee
 	var reviews []models.Review
-	var reviewResponses []ReviewResponse
+	reviewResponses := []ReviewResponse{}
 
 	tenderID := c.Param("id")
 	authorUsername := c.Query("authorUsername")
 	requesterUsername := c.Query("requesterUsername")
-	limitStr := c.DefaultQuery("limit", "5")
+	scope := c.DefaultQuery("scope", "tender")
+	order := c.DefaultQuery("order", "desc")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
 	offsetStr := c.DefaultQuery("offset", "0")
 
 	// Проверка обязательных параметров
-	if authorUsername == "" || requesterUsername == "" {
+	if requesterUsername == "" || (scope == "author" && authorUsername == "") {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
 		return
 	}
 
+	if scope != "tender" && scope != "author" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid scope parameter"})
+		return
+	}
+
+	if order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid order parameter"})
+		return
+	}
+
 	// Преобразование limit и offset в int
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
 		return
 	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
 		return
 	}
 
 	// Проверка существования пользователя, запрашивающего отзывы
-	if err := ctrl.DB.Where("username = ?", requesterUsername).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", requesterUsername).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "Requester does not exist"})
+		logError(c, err, "Requester does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка авторизации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized"})
+		logError(c, err, "User is not authorized")
 		return
 	}
 
 	// Проверка существования автора предложений
-	var author models.Employee
-	if err := ctrl.DB.Where("username = ?", authorUsername).First(&author).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"reason": "Author not found"})
-		return
+	if authorUsername != "" {
+		if err := db.Where("username = ?", authorUsername).First(&author).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"reason": "Author not found"})
+			logError(c, err, "Author not found")
+			return
+		}
+	}
+
+	query := db.Preload("Reply").Order("reviews.created_at " + order).Limit(limit).Offset(offset)
+
+	if scope == "tender" {
+		query = query.Where("reviews.bid_id IN (?)", db.Model(&models.Bid{}).Select("id").Where("tender_id = ?", tender.ID))
+		if authorUsername != "" {
+			query = query.Where("reviews.bid_author_id = ?", author.ID)
+		}
+	} else {
+		// Историю автора видят только организации, на чей тендер он подавал предложение
+		var bidCount int64
+		if err := db.Model(&models.Bid{}).Where("tender_id = ? AND author_id = ?", tender.ID, author.ID).Count(&bidCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve reviews"})
+			logError(c, err, "Failed to retrieve reviews")
+			return
+		}
+
+		if bidCount == 0 {
+			c.JSON(http.StatusForbidden, gin.H{"reason": "Author has no bids on this tender"})
+			return
+		}
+
+		// Отзывы по закрытым тендерам других организаций не раскрываются
+		requesterOrgs := db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID)
+		query = query.Select("reviews.*").
+			Joins("JOIN bids ON bids.id = reviews.bid_id").
+			Joins("JOIN tenders ON tenders.id = bids.tender_id").
+			Where("reviews.bid_author_id = ?", author.ID).
+			Where(db.Where("tenders.visibility = ?", models.VisibilityPublic).Or("tenders.organization_id IN (?)", requesterOrgs))
 	}
 
-	// Получение отзывов на предложения автора
-	if err := ctrl.DB.Where("bid_author_id = ?", author.ID).Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+	if err := query.Find(&reviews).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve reviews"})
+		logError(c, err, "Failed to retrieve reviews")
 		return
 	}
 
 	// Формирование ответа
 	for _, review := range reviews {
-		reviewResponses = append(reviewResponses, ReviewResponse{
-			ID:        review.ID.String(),
-			Feedback:  review.Description,
-			CreatedAt: review.CreatedAt.String(),
-		})
+		reviewResponses = append(reviewResponses, newReviewResponse(review))
+	}
+
+	c.JSON(http.StatusOK, reviewResponses)
+}
+
+func (ctrl ReviewController) UpdateReview(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var review models.Review
+	var employee models.Employee
+	var req UpdateReviewRequest
+
+	reviewID := c.Param("reviewId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования отзыва
+	if err := db.Where("id = ?", reviewID).First(&review).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Review not found"})
+		logError(c, err, "Review not found")
+		return
+	}
+
+	// Изменять отзыв может только его автор
+	if review.ReviewerID != employee.ID {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this review"})
+		return
+	}
+
+	if req.Description != nil {
+		review.Description = *req.Description
+	}
+	if req.Quality != nil {
+		review.Quality = req.Quality
+	}
+	if req.Timeliness != nil {
+		review.Timeliness = req.Timeliness
+	}
+	if req.Communication != nil {
+		review.Communication = req.Communication
+	}
+
+	// Старый отзыв без оценок можно дополнить только всеми оценками сразу
+	if review.Quality == nil || review.Timeliness == nil || review.Communication == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "All ratings must be specified"})
+		return
+	}
+
+	if err := db.Save(&review).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update review"})
+		logError(c, err, "Failed to update review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func (ctrl ReviewController) DeleteReview(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var review models.Review
+	var employee models.Employee
+
+	reviewID := c.Param("reviewId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования отзыва
+	if err := db.Where("id = ?", reviewID).First(&review).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Review not found"})
+		logError(c, err, "Review not found")
+		return
+	}
+
+	// Удалять отзыв может только его автор
+	if review.ReviewerID != employee.ID {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this review"})
+		return
+	}
+
+	if err := db.Delete(&review).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to delete review"})
+		logError(c, err, "Failed to delete review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func (ctrl ReviewController) ReplyToReview(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var review models.Review
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var req ReplyReviewRequest
+
+	reviewID := c.Param("reviewId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования отзыва
+	if err := db.Preload("Reply").Where("id = ?", reviewID).First(&review).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Review not found"})
+		logError(c, err, "Review not found")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", review.BidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Отвечать на отзыв может только владелец предложения
+	if ok, err := isBidOwner(db, bid, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	if review.Reply != nil {
+		c.JSON(http.StatusConflict, gin.H{"reason": "Review already has a reply"})
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	reply := models.ReviewReply{
+		ID:       uuid.New(),
+		ReviewID: review.ID,
+		AuthorID: employee.ID,
+		Text:     req.Text,
+	}
+
+	// Одновременный второй ответ отсекает уникальный индекс по отзыву
+	if err := db.Omit("Review").Create(&reply).Error; err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"reason": "Review already has a reply"})
+			logError(c, err, "Review already has a reply")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to submit reply"})
+		logError(c, err, "Failed to submit reply")
+		return
+	}
+
+	if err := ctrl.Notifications.ReviewReply(db, bid, tender, review, reply); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+	}
+
+	review.Reply = &reply
+	c.JSON(http.StatusOK, newReviewResponse(review))
+}
+
+func (ctrl ReviewController) GetMyReviews(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+	var reviews []models.Review
+	reviewResponses := []ReviewResponse{}
+
+	username := c.Query("username")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameters"})
+		return
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
+		return
+	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Предложения пользователя: собственные и, как в isBidOwner, предложения от его организаций
+	orgs := db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID)
+	colleagues := db.Table("organization_responsibles").Select("user_id").Where("organization_id IN (?)", orgs)
+	ownBids := db.Model(&models.Bid{}).Select("id").Where("author_id = ?", employee.ID).Or("author_type = ? AND author_id IN (?)", models.AuthorOrganization, colleagues)
+
+	query := db.Preload("Reply").Where("bid_id IN (?)", ownBids).Order("created_at DESC").Limit(limit).Offset(offset)
+
+	if err := query.Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve reviews"})
+		logError(c, err, "Failed to retrieve reviews")
+		return
+	}
+
+	for _, review := range reviews {
+		reviewResponses = append(reviewResponses, newReviewResponse(review))
 	}
 
 	c.JSON(http.StatusOK, reviewResponses)
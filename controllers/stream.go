@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/events"
+	"myapp/models"
+)
+
+// Максимальное количество событий, отправляемых при переподключении
+const streamReplayLimit = 1000
+
+const streamHeartbeat = 30 * time.Second
+
+type StreamController struct {
+	DB     *gorm.DB
+	Broker *events.Broker
+}
+
+type StreamEvent struct {
+	ID        int64            `json:"id"`
+	Type      models.EventType `json:"type"`
+	TenderID  uuid.UUID        `json:"tenderId"`
+	BidID     *uuid.UUID       `json:"bidId,omitempty"`
+	Payload   json.RawMessage  `json:"payload"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// streamSubscriber определяет, какие события доступны пользователю
+type streamSubscriber struct {
+	db            *gorm.DB
+	employee      models.Employee
+	organizations map[uuid.UUID]bool
+	bidTenders    map[uuid.UUID]bool
+}
+
+func (s *streamSubscriber) allowed(event *models.Event) bool {
+	// Ответственные лица организации видят все события по своим тендерам
+	if s.organizations[event.OrganizationID] {
+		return true
+	}
+
+	// Автор предложения видит события по своему предложению
+	if event.BidAuthorID != nil && *event.BidAuthorID == s.employee.ID {
+		return true
+	}
+
+	// Об изменении статуса тендера и ходе аукциона узнают все, кто подавал на него предложения
+	if event.Type == models.EventTenderStatus || event.Type == models.EventAuctionRound || event.Type == models.EventAuctionPrice {
+		if allowed, ok := s.bidTenders[event.TenderID]; ok {
+			return allowed
+		}
+
+		// При ошибке событие не отправляется, а результат не запоминается, чтобы проверить доступ при следующем событии
+		var count int64
+		if err := s.db.Model(&models.Bid{}).Where("tender_id = ? AND author_id = ?", event.TenderID, s.employee.ID).Count(&count).Error; err != nil {
+			slog.ErrorContext(s.db.Statement.Context, "Failed to check event access", "tender_id", event.TenderID, "error", err)
+			return false
+		}
+		s.bidTenders[event.TenderID] = count > 0
+
+		return count > 0
+	}
+
+	return false
+}
+
+func (ctrl StreamController) Stream(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var employee models.Employee
+	var orgResps []models.OrganizationResponsible
+	var lastEventID int64
+
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Идентификатор последнего полученного события: заголовок при автоматическом переподключении браузера
+	// или параметр запроса для клиентов, которые не умеют передавать заголовки
+	lastEventIDStr := c.GetHeader("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = c.Query("lastEventId")
+	}
+
+	if lastEventIDStr != "" {
+		var err error
+		lastEventID, err = strconv.ParseInt(lastEventIDStr, 10, 64)
+		if err != nil || lastEventID < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid Last-Event-ID"})
+			logError(c, err, "Invalid Last-Event-ID")
+			return
+		}
+	}
+
+	if err := db.Where("user_id = ?", employee.ID).Find(&orgResps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve organizations"})
+		logError(c, err, "Failed to retrieve organizations")
+		return
+	}
+
+	subscriber := &streamSubscriber{
+		db:            db,
+		employee:      employee,
+		organizations: make(map[uuid.UUID]bool),
+		bidTenders:    make(map[uuid.UUID]bool),
+	}
+	for _, orgResp := range orgResps {
+		subscriber.organizations[orgResp.OrganizationID] = true
+	}
+
+	delivered := deliveredEvents{}
+
+	// Подписка оформляется до чтения пропущенных событий, чтобы ничего не потерять между ними
+	sub := ctrl.Broker.Subscribe()
+	defer ctrl.Broker.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// Отправка событий, пропущенных с момента последнего подключения
+	if lastEventIDStr != "" {
+		// События с идентификатором не больше Last-Event-ID, созданные за последние GapTimeout, могли быть
+		// зафиксированы после отправки клиенту события Last-Event-ID, поэтому отправляются повторно:
+		// клиент отбрасывает уже полученные по идентификатору
+		var missed []models.Event
+		err := db.Where("id > ? OR created_at > ?", lastEventID, time.Now().Add(-events.GapTimeout)).
+			Order("id").Limit(streamReplayLimit).Find(&missed).Error
+		if err != nil {
+			return
+		}
+
+		for i := range missed {
+			delivered.add(missed[i].ID)
+			if subscriber.allowed(&missed[i]) {
+				writeStreamEvent(c, &missed[i])
+			}
+		}
+		c.Writer.Flush()
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			// Комментарий не обрабатывается клиентом, но не даёт прокси закрыть соединение
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+			delivered.prune()
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if delivered.has(event.ID) {
+				continue
+			}
+			delivered.add(event.ID)
+			if !subscriber.allowed(event) {
+				continue
+			}
+			writeStreamEvent(c, event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// deliveredEvents - идентификаторы событий, уже обработанных потоком. Брокер раздаёт события с пропущенными
+// ранее идентификаторами позже событий с большими идентификаторами, поэтому повторы отсекаются по набору,
+// а не по последнему идентификатору. Пропуск брокер ждёт не дольше events.GapTimeout, столько же хранится
+// и идентификатор
+type deliveredEvents map[int64]time.Time
+
+func (d deliveredEvents) add(id int64) {
+	d[id] = time.Now()
+}
+
+// prune забывает идентификаторы, которые брокер уже не может раздать повторно
+func (d deliveredEvents) prune() {
+	for id, at := range d {
+		if time.Since(at) > events.GapTimeout {
+			delete(d, id)
+		}
+	}
+}
+
+func (d deliveredEvents) has(id int64) bool {
+	_, ok := d[id]
+	return ok
+}
+
+func writeStreamEvent(c *gin.Context, event *models.Event) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: string(event.Type),
+		Data: StreamEvent{
+			ID:        event.ID,
+			Type:      event.Type,
+			TenderID:  event.TenderID,
+			BidID:     event.BidID,
+			Payload:   json.RawMessage(event.Payload),
+			CreatedAt: event.CreatedAt,
+		},
+	})
+}
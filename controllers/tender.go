@@ -1,7 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"log/slog"
+	"myapp/events"
+	"myapp/metrics"
 	"myapp/models"
+	"myapp/notify"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,10 +14,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/workflow"
 )
 
 type TenderController struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Notifications *notify.Service
+	Pagination    config.Pagination
 }
 
 type CreateTenderRequest struct {
@@ -21,6 +30,18 @@ type CreateTenderRequest struct {
 	ServiceType     models.ServiceType `json:"serviceType" binding:"required"`
 	OrganizationID  uuid.UUID          `json:"organizationId" binding:"required"`
 	CreatorUsername string             `json:"creatorUsername" binding:"required"`
+	Sealed          bool               `json:"sealed"`
+	Deadline        *time.Time         `json:"deadline,omitempty"`
+	AuctionMode     bool               `json:"auctionMode"`
+	Visibility      models.Visibility  `json:"visibility,omitempty"`
+	Lots            []CreateLotRequest `json:"lots,omitempty" binding:"dive"`
+}
+
+type CreateLotRequest struct {
+	Name        string  `json:"name" binding:"required,max=100"`
+	Description string  `json:"description" binding:"required,max=500"`
+	Budget      float64 `json:"budget" binding:"gt=0"`
+	Quantity    int     `json:"quantity" binding:"gt=0"`
 }
 
 type UpdateTenderRequest struct {
@@ -28,32 +49,56 @@ type UpdateTenderRequest struct {
 	Description string             `json:"description,omitempty"`
 	ServiceType models.ServiceType `json:"serviceType,omitempty"`
 	Status      models.Status      `json:"status,omitempty"`
+	Deadline    *time.Time         `json:"deadline,omitempty"`
 }
 
 func (ctrl TenderController) GetTenders(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tenders []models.Tender
 	var err error
 
 	// Получение параметров запроса
-	limitStr := c.DefaultQuery("limit", "5")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
 	offsetStr := c.DefaultQuery("offset", "0")
 	serviceTypes := c.QueryArray("service_type")
+	username := c.Query("username")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
 		return
 	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
 		return
 	}
 
-	// Поскольку в этой ручке нет параметра username, то отображаются только опубликованные тендеры
-	// которые доступны всем пользователям
-	query := ctrl.DB.Limit(limit).Offset(offset).Order("name").Where("status = ?", models.Published)
+	// Отображаются только опубликованные тендеры. Закрытые тендеры видны лишь приглашённым пользователям
+	// и ответственным лицам организации, поэтому без параметра username показываются только публичные
+	query := db.Limit(limit).Offset(offset).Order("name").Where("status = ?", models.Published)
+
+	if username == "" {
+		query = query.Where("visibility = ?", models.VisibilityPublic)
+	} else {
+		var employee models.Employee
+
+		// Проверка существования пользователя
+		if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+			logError(c, err, "User does not exist")
+			return
+		}
+
+		invited := db.Model(&models.TenderInvitation{}).Select("tender_id").Where(invitedScope(db, employee.ID))
+		own := db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID)
+		query = query.Where(db.Where("visibility = ?", models.VisibilityPublic).Or("id IN (?)", invited).Or("organization_id IN (?)", own))
+	}
 
 	if len(serviceTypes) > 0 {
 		query = query.Where("service_type IN ?", serviceTypes)
@@ -61,6 +106,7 @@ func (ctrl TenderController) GetTenders(c *gin.Context) {
 
 	if err = query.Find(&tenders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tenders"})
+		logError(c, err, "Failed to retrieve tenders")
 		return
 	}
 
@@ -68,6 +114,8 @@ func (ctrl TenderController) GetTenders(c *gin.Context) {
 }
 
 func (ctrl TenderController) CreateTender(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var req CreateTenderRequest
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -75,24 +123,28 @@ func (ctrl TenderController) CreateTender(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
 		return
 	}
 
 	// Проверка существования организации
-	if err := ctrl.DB.Where("id = ?", req.OrganizationID).First(&organization).Error; err != nil {
+	if err := db.Where("id = ?", req.OrganizationID).First(&organization).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Organization does not exist"})
+		logError(c, err, "Organization does not exist")
 		return
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", req.CreatorUsername).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", req.CreatorUsername).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, req.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, req.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
 		return
 	}
 
@@ -108,6 +160,28 @@ func (ctrl TenderController) CreateTender(c *gin.Context) {
 		return
 	}
 
+	// Срок подачи предложений должен быть в будущем
+	if req.Deadline != nil && !req.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid deadline"})
+		return
+	}
+
+	// Обратный аукцион проводится только по тендерам на поставку с открытыми однолотовыми предложениями
+	if req.AuctionMode && (req.ServiceType != models.Delivery || req.Sealed || len(req.Lots) > 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Auction mode is only available for single-lot unsealed Delivery tenders"})
+		return
+	}
+
+	// По умолчанию тендер публичный
+	if req.Visibility == "" {
+		req.Visibility = models.VisibilityPublic
+	}
+
+	if req.Visibility != models.VisibilityPublic && req.Visibility != models.VisibilityInviteOnly {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid visibility"})
+		return
+	}
+
 	tender := models.Tender{
 		ID:             uuid.New(),
 		Name:           req.Name,
@@ -115,24 +189,44 @@ func (ctrl TenderController) CreateTender(c *gin.Context) {
 		ServiceType:    req.ServiceType,
 		Status:         models.Created,
 		OrganizationID: req.OrganizationID,
+		Sealed:         req.Sealed,
+		Deadline:       req.Deadline,
+		AuctionMode:    req.AuctionMode,
+		Visibility:     req.Visibility,
 		CreatedAt:      time.Now(),
 	}
 
-	if err := ctrl.DB.Create(&tender).Error; err != nil {
+	// Лоты создаются вместе с тендером
+	for _, lotReq := range req.Lots {
+		tender.Lots = append(tender.Lots, models.TenderLot{
+			ID:          uuid.New(),
+			Name:        lotReq.Name,
+			Description: lotReq.Description,
+			Budget:      lotReq.Budget,
+			Quantity:    lotReq.Quantity,
+			Status:      models.LotOpen,
+		})
+	}
+
+	if err := db.Create(&tender).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create tender"})
+		logError(c, err, "Failed to create tender")
 		return
 	}
+	metrics.TendersCreated.Inc()
 
 	c.JSON(http.StatusOK, tender)
 }
 
 func (ctrl TenderController) GetUserTenders(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tenders []models.Tender
 	var employee models.Employee
 	var err error
 
 	// Получение параметров запроса
-	limitStr := c.DefaultQuery("limit", "5")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(ctrl.Pagination.DefaultLimit))
 	offsetStr := c.DefaultQuery("offset", "0")
 	username := c.Query("username")
 
@@ -145,25 +239,30 @@ func (ctrl TenderController) GetUserTenders(c *gin.Context) {
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit parameter"})
+		logError(c, err, "Invalid limit parameter")
 		return
 	}
+	limit = min(limit, ctrl.Pagination.MaxLimit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil || offset < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset parameter"})
+		logError(c, err, "Invalid offset parameter")
 		return
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
-	query := ctrl.DB.Limit(limit).Offset(offset).Order("name").Where("organization_id IN (?)", ctrl.DB.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID))
+	query := db.Limit(limit).Offset(offset).Order("name").Where("organization_id IN (?)", db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID))
 
 	if err = query.Find(&tenders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tenders"})
+		logError(c, err, "Failed to retrieve tenders")
 		return
 	}
 
@@ -171,6 +270,8 @@ func (ctrl TenderController) GetUserTenders(c *gin.Context) {
 }
 
 func (ctrl TenderController) GetTenderStatus(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -185,20 +286,23 @@ func (ctrl TenderController) GetTenderStatus(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
 		return
 	}
 
@@ -206,6 +310,8 @@ func (ctrl TenderController) GetTenderStatus(c *gin.Context) {
 }
 
 func (ctrl TenderController) UpdateTenderStatus(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -221,20 +327,23 @@ func (ctrl TenderController) UpdateTenderStatus(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
 		return
 	}
 
@@ -252,24 +361,45 @@ func (ctrl TenderController) UpdateTenderStatus(c *gin.Context) {
 		return
 	}
 
+	// Закрытие проходит через общий переход: закрыть можно только опубликованный тендер
+	if newStatus == models.Closed {
+		if !ctrl.closeTender(c, db, &tender) {
+			return
+		}
+		c.JSON(http.StatusOK, tender)
+		return
+	}
+
 	// Обновление статуса тендера
+	oldStatus := tender.Status
 	tender.Status = newStatus
 
-	if err := ctrl.DB.Model(&tender).Update("status", newStatus).Error; err != nil {
+	if err := db.Model(&tender).Update("status", newStatus).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update tender status"})
+		logError(c, err, "Failed to update tender status")
 		return
 	}
 
 	// Повторная загрузка тендера для получения актуальной версии тендера после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload tender"})
+		logError(c, err, "Failed to reload tender")
 		return
 	}
 
+	if tender.Status != oldStatus {
+		metrics.TenderStatusChanges.WithLabelValues(string(tender.Status)).Inc()
+		if err := events.TenderStatusChanged(db, tender); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish tender status event", "error", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, tender)
 }
 
 func (ctrl TenderController) EditTender(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tender models.Tender
 	var employee models.Employee
 	var orgResp models.OrganizationResponsible
@@ -285,38 +415,87 @@ func (ctrl TenderController) EditTender(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
 		return
 	}
 
 	// Обновление полей тендера
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Срок подачи предложений должен быть в будущем
+	if req.Deadline != nil && !req.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid deadline"})
 		return
 	}
 
+	// Срок запечатанного тендера с поданными предложениями нельзя перенести на более ранний:
+	// предложения вскрылись бы раньше, чем рассчитывали участники
+	if req.Deadline != nil && tender.Sealed && tender.Deadline != nil && req.Deadline.Before(*tender.Deadline) {
+		var bidCount int64
+		if err := db.Model(&models.Bid{}).Where("tender_id = ?", tender.ID).Count(&bidCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve bids"})
+			logError(c, err, "Failed to retrieve bids")
+			return
+		}
+		if bidCount > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Deadline of a sealed tender with bids cannot be moved earlier"})
+			return
+		}
+	}
+
+	// Закрытие выполняется общим переходом после обновления остальных полей
+	closing := req.Status == models.Closed && tender.Status != models.Closed
+	if closing {
+		if tender.Status != models.Published {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+			return
+		}
+		req.Status = ""
+	}
+
 	// Обновление полей тендера
-	if err := ctrl.DB.Model(&tender).Updates(req).Error; err != nil {
+	oldStatus := tender.Status
+	if err := db.Model(&tender).Updates(req).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update tender"})
+		logError(c, err, "Failed to update tender")
 		return
 	}
 
 	// Повторная загрузка тендера для получения актуальной версии тендера после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload tender"})
+		logError(c, err, "Failed to reload tender")
+		return
+	}
+
+	if tender.Status != oldStatus {
+		metrics.TenderStatusChanges.WithLabelValues(string(tender.Status)).Inc()
+		if err := events.TenderStatusChanged(db, tender); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish tender status event", "error", err)
+		}
+	}
+
+	if closing && !ctrl.closeTender(c, db, &tender) {
 		return
 	}
 
@@ -324,6 +503,8 @@ func (ctrl TenderController) EditTender(c *gin.Context) {
 }
 
 func (ctrl TenderController) RollbackTender(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
 	var tender models.Tender
 	var tenderHistory models.TenderHistory
 	var employee models.Employee
@@ -340,20 +521,23 @@ func (ctrl TenderController) RollbackTender(c *gin.Context) {
 	}
 
 	// Проверка существования пользователя
-	if err := ctrl.DB.Where("username = ?", username).First(&employee).Error; err != nil {
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
 		return
 	}
 
 	// Проверка существования тендера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
 		return
 	}
 
 	// Проверка, что пользователь является ответственным лицом организации
-	if err := ctrl.DB.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
 		return
 	}
 
@@ -361,31 +545,252 @@ func (ctrl TenderController) RollbackTender(c *gin.Context) {
 	version, err := strconv.Atoi(versionStr)
 	if err != nil || version <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid version parameter"})
+		logError(c, err, "Invalid version parameter")
 		return
 	}
 
 	// Поиск истории тендера по версии
-	if err := ctrl.DB.Where("tender_id = ? AND version = ?", tenderID, version).First(&tenderHistory).Error; err != nil {
+	if err := db.Where("tender_id = ? AND version = ?", tenderID, version).First(&tenderHistory).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender version not found"})
+		logError(c, err, "Tender version not found")
+		return
+	}
+
+	// Откат к закрытой версии закрывает тендер общим переходом после отката остальных полей
+	closing := tenderHistory.Status == models.Closed && tender.Status != models.Closed
+	if closing && tender.Status != models.Published {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
 		return
 	}
 
 	// Откат тендера к указанной версии
+	oldStatus := tender.Status
 	tender.Name = tenderHistory.Name
 	tender.Description = tenderHistory.Description
 	tender.ServiceType = tenderHistory.ServiceType
-	tender.Status = tenderHistory.Status
+	if !closing {
+		tender.Status = tenderHistory.Status
+	}
 
-	if err := ctrl.DB.Model(&tender).Updates(tender).Error; err != nil {
+	if err := db.Model(&tender).Updates(tender).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to rollback tender"})
+		logError(c, err, "Failed to rollback tender")
 		return
 	}
 
 	// Повторная загрузка тендера для получения актуальной версии тендера после срабатывания триггера
-	if err := ctrl.DB.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to reload tender"})
+		logError(c, err, "Failed to reload tender")
+		return
+	}
+
+	if tender.Status != oldStatus {
+		metrics.TenderStatusChanges.WithLabelValues(string(tender.Status)).Inc()
+		if err := events.TenderStatusChanged(db, tender); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to publish tender status event", "error", err)
+		}
+	}
+
+	if closing && !ctrl.closeTender(c, db, &tender) {
 		return
 	}
 
 	c.JSON(http.StatusOK, tender)
 }
+
+func (ctrl TenderController) OpenTenderBids(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Проверка, что предложения тендера ещё запечатаны
+	sealed, err := bidsSealed(db, tender)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check tender bids"})
+		logError(c, err, "Failed to check tender bids")
+		return
+	}
+
+	if !sealed {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are not sealed"})
+		return
+	}
+
+	// Запись о вскрытии предложений
+	opening := models.TenderOpening{
+		ID:       uuid.New(),
+		TenderID: tender.ID,
+		OpenedBy: &employee.ID,
+		Reason:   models.OpeningManual,
+	}
+
+	if err := db.Omit("Tender").Create(&opening).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to open tender bids"})
+		logError(c, err, "Failed to open tender bids")
+		return
+	}
+
+	c.JSON(http.StatusOK, opening)
+}
+
+func (ctrl TenderController) CreateLot(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var orgResp models.OrganizationResponsible
+	var req CreateLotRequest
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка, что пользователь является ответственным лицом организации
+	if err := db.Where("user_id = ? AND organization_id = ?", employee.ID, tender.OrganizationID).First(&orgResp).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this organization"})
+		logError(c, err, "User is not authorized for this organization")
+		return
+	}
+
+	// Состав лотов меняется только до публикации тендера
+	if tender.Status != models.Created {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Lots can only be added to a tender that is not published"})
+		return
+	}
+
+	lot := models.TenderLot{
+		ID:          uuid.New(),
+		TenderID:    tender.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Budget:      req.Budget,
+		Quantity:    req.Quantity,
+		Status:      models.LotOpen,
+	}
+
+	if err := db.Create(&lot).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to create lot"})
+		logError(c, err, "Failed to create lot")
+		return
+	}
+
+	c.JSON(http.StatusOK, lot)
+}
+
+func (ctrl TenderController) GetLots(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var tender models.Tender
+	var employee models.Employee
+	var lots []models.TenderLot
+
+	tenderID := c.Param("tenderId")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", tenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к тендеру
+	if ok, err := canViewTender(db, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this tender"})
+		logError(c, err, "User is not authorized for this tender")
+		return
+	}
+
+	if err := db.Order("created_at").Where("tender_id = ?", tender.ID).Find(&lots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve lots"})
+		logError(c, err, "Failed to retrieve lots")
+		return
+	}
+
+	c.JSON(http.StatusOK, lots)
+}
+
+// closeTender закрывает тендер общим переходом. При ошибке отправляет ответ и возвращает false
+func (ctrl TenderController) closeTender(c *gin.Context, db *gorm.DB, tender *models.Tender) bool {
+	if err := workflow.CloseTender(db, ctrl.Notifications, tender); err != nil {
+		if errors.Is(err, workflow.ErrNotPublished) {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to update tender status"})
+		logError(c, err, "Failed to update tender status")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"myapp/events"
+	"myapp/models"
+	"myapp/notify"
+)
+
+// errBidChanged возвращается из транзакции, если предложение изменили после проверок обработчика
+var errBidChanged = errors.New("bid was changed by another request")
+
+type WithdrawalController struct {
+	DB            *gorm.DB
+	Notifications *notify.Service
+}
+
+type WithdrawBidRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (ctrl WithdrawalController) WithdrawBid(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var req WithdrawBidRequest
+
+	bidID := c.Param("bidID")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Причина отзыва обязательна
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		logError(c, err, "Invalid request body")
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка авторизации
+	if ok, err := isBidOwner(db, bid, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	// Отозвать можно только поданное предложение, неопубликованное достаточно отменить
+	if bid.Status != models.BidPublished {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not published"})
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	if tender.Status != models.Published {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+		return
+	}
+
+	// После первого решения организации предложение отозвать нельзя
+	var decisionCount int64
+	if err := db.Model(&models.Decision{}).Where("bid_id = ?", bid.ID).Count(&decisionCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to check decisions"})
+		logError(c, err, "Failed to check decisions")
+		return
+	}
+
+	if decisionCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid already has a decision"})
+		return
+	}
+
+	var withdrawal models.BidWithdrawal
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка предложения упорядочивает отзыв с принятием решения (workflow.SubmitDecision блокирует его так же),
+		// а условие проверяется следующим запросом, который видит решение, зафиксированное до снятия блокировки
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", bid.ID).First(&models.Bid{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Bid{}).
+			Where("id = ? AND status = ? AND NOT EXISTS (SELECT 1 FROM decisions WHERE decisions.bid_id = bids.id)", bid.ID, models.BidPublished).
+			Update("status", models.BidWithdrawn)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errBidChanged
+		}
+
+		// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
+		if err := tx.Where("id = ?", bid.ID).First(&bid).Error; err != nil {
+			return err
+		}
+
+		withdrawal = models.BidWithdrawal{
+			ID:         uuid.New(),
+			BidID:      bid.ID,
+			AuthorID:   employee.ID,
+			Reason:     req.Reason,
+			BidVersion: bid.Version,
+		}
+
+		return tx.Omit("Bid").Create(&withdrawal).Error
+	})
+	if errors.Is(err, errBidChanged) {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid already has a decision or is not published"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to withdraw bid"})
+		logError(c, err, "Failed to withdraw bid")
+		return
+	}
+
+	if err := events.BidWithdrawn(db, withdrawal, bid, tender); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to publish bid withdrawal event", "error", err)
+	}
+
+	c.JSON(http.StatusOK, withdrawal)
+}
+
+func (ctrl WithdrawalController) ResubmitBid(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var withdrawal models.BidWithdrawal
+
+	bidID := c.Param("bidID")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка авторизации
+	if ok, err := isBidOwner(db, bid, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	if bid.Status != models.BidWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Bid is not withdrawn"})
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	if tender.Status != models.Published {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender is not published"})
+		return
+	}
+
+	// Повторная подача после вскрытия запечатанного тендера равносильна подаче нового предложения
+	if opened, err := bidsOpened(db, tender); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve tender openings"})
+		logError(c, err, "Failed to retrieve tender openings")
+		return
+	} else if opened {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Tender bids are already opened"})
+		return
+	}
+
+	// Последний отзыв, к которому относится повторная подача
+	if err := db.Where("bid_id = ? AND resubmitted_at IS NULL", bid.ID).Order("created_at DESC").First(&withdrawal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve withdrawal"})
+		logError(c, err, "Failed to retrieve withdrawal")
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Повторная подача создаёт новую версию предложения, запись об отзыве остаётся в истории
+		if err := tx.Model(&bid).Update("status", models.BidPublished).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", bid.ID).First(&bid).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		withdrawal.ResubmittedVersion = &bid.Version
+		withdrawal.ResubmittedAt = &now
+
+		return tx.Model(&withdrawal).Updates(map[string]any{"resubmitted_version": bid.Version, "resubmitted_at": now}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to resubmit bid"})
+		logError(c, err, "Failed to resubmit bid")
+		return
+	}
+
+	if err := events.BidPublished(db, bid); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to publish bid event", "error", err)
+	}
+	if err := ctrl.Notifications.NewBid(db, bid); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to save notifications", "error", err)
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+func (ctrl WithdrawalController) GetWithdrawals(c *gin.Context) {
+	db := requestDB(c, ctrl.DB)
+
+	var bid models.Bid
+	var tender models.Tender
+	var employee models.Employee
+	var withdrawals []models.BidWithdrawal
+
+	bidID := c.Param("id")
+	username := c.Query("username")
+
+	// Проверка обязательных параметров
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Missing required parameter(s)"})
+		return
+	}
+
+	// Проверка существования пользователя
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		logError(c, err, "User does not exist")
+		return
+	}
+
+	// Проверка существования предложения
+	if err := db.Where("id = ?", bidID).First(&bid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
+		logError(c, err, "Bid not found")
+		return
+	}
+
+	// Проверка существования тендера
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
+		logError(c, err, "Tender not found")
+		return
+	}
+
+	// Проверка доступа к предложению
+	if ok, err := canViewBid(db, bid, tender, employee); err != nil || !ok {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "User is not authorized for this bid"})
+		logError(c, err, "User is not authorized for this bid")
+		return
+	}
+
+	if err := db.Order("created_at").Where("bid_id = ?", bid.ID).Find(&withdrawals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve withdrawals"})
+		logError(c, err, "Failed to retrieve withdrawals")
+		return
+	}
+
+	c.JSON(http.StatusOK, withdrawals)
+}
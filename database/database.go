@@ -0,0 +1,306 @@
+// Package database подключается к Postgres и применяет миграции схемы
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/health"
+	"myapp/logging"
+	"myapp/models"
+)
+
+// schema - модели, таблицы которых создаёт автоматическая миграция
+var schema = []any{
+	&models.Employee{}, &models.Organization{}, &models.OrganizationResponsible{}, &models.Tender{}, &models.TenderHistory{},
+	&models.Bid{}, &models.BidHistory{}, &models.Decision{}, &models.Review{}, &models.Event{}, &models.NotificationPreference{},
+	&models.Notification{}, &models.Attachment{}, &models.TenderQuestion{}, &models.TenderOpening{}, &models.TenderLot{},
+	&models.BidLot{}, &models.AuctionRound{}, &models.TenderInvitation{}, &models.BidWithdrawal{}, &models.ReviewReply{},
+	&models.Contract{},
+}
+
+// Open подключается к базе данных и настраивает пул соединений
+func Open(cfg config.DB) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.Conn), &gorm.Config{
+		Logger: logging.GormLogger{SlowThreshold: cfg.SlowQueryThreshold},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db, nil
+}
+
+// Migrate создаёт расширения, типы, таблицы и триггеры истории версий
+func Migrate(db *gorm.DB) error {
+	// Установка расширения uuid-ossp
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`).Error; err != nil {
+		return fmt.Errorf("failed to create extension uuid-ossp: %w", err)
+	}
+
+	// Создание типа organization_type
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE organization_type AS ENUM ('IE', 'LLC', 'JSC');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type organization_type: %w", err)
+	}
+
+	// Создание типа service_type
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE service_type AS ENUM ('Construction', 'Delivery', 'Manufacture');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type service_type: %w", err)
+	}
+
+	// Создание типа status
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE status AS ENUM ('Created', 'Published', 'Closed');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type status: %w", err)
+	}
+
+	// Создание типа bid_status
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE bid_status AS ENUM ('Created', 'Published', 'Canceled');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type bid_status: %w", err)
+	}
+
+	// Статус отозванного предложения добавлен позже, поэтому расширяет уже существующий тип
+	if err := db.Exec(`ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Withdrawn';`).Error; err != nil {
+		return fmt.Errorf("failed to extend type bid_status: %w", err)
+	}
+
+	// Создание типа author_type
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE author_type AS ENUM ('Organization', 'User');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type author_type: %w", err)
+	}
+
+	// Создание типа decision_type
+	if err := db.Exec(`
+        DO $$ BEGIN
+            CREATE TYPE decision_type AS ENUM ('Approved', 'Rejected');
+        EXCEPTION
+            WHEN duplicate_object THEN null;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create type decision_type: %w", err)
+	}
+
+	// В отзывах раньше вместо автора предложения сохранялся автор отзыва.
+	// Переносим его в отдельную колонку и восстанавливаем автора предложения по самому предложению
+	if err := db.Exec(`
+        DO $$ BEGIN
+            IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'reviews')
+               AND NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'reviews' AND column_name = 'reviewer_id') THEN
+                ALTER TABLE reviews ADD COLUMN reviewer_id uuid;
+                UPDATE reviews r SET reviewer_id = r.bid_author_id, bid_author_id = b.author_id FROM bids b WHERE b.id = r.bid_id;
+                ALTER TABLE reviews ALTER COLUMN reviewer_id SET NOT NULL;
+            END IF;
+        END $$;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to migrate reviews: %w", err)
+	}
+
+	// Автоматическая миграция таблиц
+	if err := db.AutoMigrate(schema...); err != nil {
+		return fmt.Errorf("failed to migrate tables: %w", err)
+	}
+
+	// Номера раундов аукциона уникальны в пределах тендера, и открытым может быть только один раунд,
+	// даже если раунды открывают одновременно
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_auction_round_number ON auction_rounds (tender_id, number)`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_auction_round_number: %w", err)
+	}
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_auction_round_open ON auction_rounds (tender_id) WHERE status = 'Open'`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_auction_round_open: %w", err)
+	}
+
+	// Сотрудника или организацию приглашают в тендер один раз. Пустые employee_id и organization_id
+	// не считаются совпадающими, поэтому каждый индекс действует только для своего вида приглашений
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tender_invitation_employee ON tender_invitations (tender_id, employee_id)`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_tender_invitation_employee: %w", err)
+	}
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tender_invitation_organization ON tender_invitations (tender_id, organization_id)`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_tender_invitation_organization: %w", err)
+	}
+
+	// По предложению составляется один договор, а по многолотовому тендеру - один договор на каждый лот предложения.
+	// Пустые lot_id не считаются совпадающими, поэтому договоры без лота ограничивает отдельный частичный индекс
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_contract_bid_lot ON contracts (bid_id, lot_id) WHERE lot_id IS NOT NULL`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_contract_bid_lot: %w", err)
+	}
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_contract_bid ON contracts (bid_id) WHERE lot_id IS NULL`).Error; err != nil {
+		return fmt.Errorf("failed to create index idx_contract_bid: %w", err)
+	}
+
+	// Создание функции для триггера обновления истории тендера
+	if err := db.Exec(`
+        CREATE OR REPLACE FUNCTION update_tender_history() RETURNS TRIGGER AS $$
+        BEGIN
+            -- Вставка записи в таблицу истории до изменений
+            INSERT INTO tender_histories (id, tender_id, name, description, service_type, status, version, created_at)
+            VALUES (uuid_generate_v4(), OLD.id, OLD.name, OLD.description, OLD.service_type, OLD.status, OLD.version, NOW());
+
+            -- Увеличение версии тендера
+            NEW.version := OLD.version + 1;
+
+            RETURN NEW;
+        END;
+        $$ LANGUAGE plpgsql;
+    `).Error; err != nil {
+		return fmt.Errorf("failed to create function update_tender_history: %w", err)
+	}
+
+	// Создание триггера обновления истории тендера
+	if err := db.Exec(`
+    DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'tender_update_trigger') THEN
+            CREATE TRIGGER tender_update_trigger
+            BEFORE UPDATE ON tenders
+            FOR EACH ROW
+            EXECUTE FUNCTION update_tender_history();
+        END IF;
+    END $$;
+`).Error; err != nil {
+		return fmt.Errorf("failed to create trigger tender_update_trigger: %w", err)
+	}
+
+	// Создание функции для триггера обновления истории предложений
+	if err := db.Exec(`
+    CREATE OR REPLACE FUNCTION update_bid_history() RETURNS TRIGGER AS $$
+    BEGIN
+        -- Вставка записи в таблицу истории до изменений
+        INSERT INTO bid_histories (id, bid_id, name, description, status, price, version, created_at)
+        VALUES (uuid_generate_v4(), OLD.id, OLD.name, OLD.description, OLD.status, OLD.price, OLD.version, NOW());
+
+        -- Увеличение версии предложения
+        NEW.version := OLD.version + 1;
+
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+`).Error; err != nil {
+		return fmt.Errorf("failed to create function update_bid_history: %w", err)
+	}
+
+	// Создание триггера обновления истории предложений
+	if err := db.Exec(`
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'bid_update_trigger') THEN
+        CREATE TRIGGER bid_update_trigger
+        BEFORE UPDATE ON bids
+        FOR EACH ROW
+        EXECUTE FUNCTION update_bid_history();
+    END IF;
+END $$;
+`).Error; err != nil {
+		return fmt.Errorf("failed to create trigger bid_update_trigger: %w", err)
+	}
+
+	// Предложения от организаций раньше не хранили организацию: она определялась по ответственному лицу-автору.
+	// Заполняем её так же, как при подаче предложения, не создавая версий в истории предложений
+	if err := db.Exec(`
+    DO $$
+    BEGIN
+        IF EXISTS (
+            SELECT 1 FROM bids b JOIN organization_responsibles o ON o.user_id = b.author_id
+            WHERE b.author_type = 'Organization' AND b.organization_id IS NULL
+        ) THEN
+            ALTER TABLE bids DISABLE TRIGGER bid_update_trigger;
+            UPDATE bids b SET organization_id = o.organization_id
+            FROM (SELECT DISTINCT ON (user_id) user_id, organization_id FROM organization_responsibles ORDER BY user_id, id) o
+            WHERE o.user_id = b.author_id AND b.author_type = 'Organization' AND b.organization_id IS NULL;
+            ALTER TABLE bids ENABLE TRIGGER bid_update_trigger;
+        END IF;
+    END $$;
+`).Error; err != nil {
+		return fmt.Errorf("failed to backfill bid organizations: %w", err)
+	}
+
+	// Договоры раньше определяли организацию поставщика при каждом обращении; закрепляем организацию предложения
+	if err := db.Exec(`
+    UPDATE contracts c SET supplier_organization_id = b.organization_id
+    FROM bids b
+    WHERE b.id = c.bid_id AND c.supplier_type = 'Organization' AND c.supplier_organization_id IS NULL AND b.organization_id IS NOT NULL
+`).Error; err != nil {
+		return fmt.Errorf("failed to backfill contract supplier organizations: %w", err)
+	}
+
+	return nil
+}
+
+// SchemaCheck проверяет, что миграции применены: таблицы всех моделей существуют в текущей схеме
+func SchemaCheck(db *gorm.DB) (health.Check, error) {
+	tables := make([]string, 0, len(schema))
+	for _, model := range schema {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		tables = append(tables, stmt.Schema.Table)
+	}
+
+	return func(ctx context.Context) error {
+		var existing []string
+		err := db.WithContext(ctx).
+			Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name IN ?", tables).
+			Scan(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		found := make(map[string]bool, len(existing))
+		for _, table := range existing {
+			found[table] = true
+		}
+
+		var missing []string
+		for _, table := range tables {
+			if !found[table] {
+				missing = append(missing, table)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,107 @@
+package documents
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"myapp/models"
+)
+
+const fontFamily = "contract"
+
+//go:embed templates/contract.tmpl
+var contractTemplate string
+
+var contractTmpl = template.Must(template.New("contract").Funcs(template.FuncMap{
+	"date": func(v any) string {
+		switch t := v.(type) {
+		case time.Time:
+			return t.Format("02.01.2006")
+		case *time.Time:
+			if t != nil {
+				return t.Format("02.01.2006")
+			}
+		}
+		return ""
+	},
+	"money": func(v *float64) string {
+		return fmt.Sprintf("%.2f", *v)
+	},
+}).Parse(contractTemplate))
+
+// ContractData - данные, доступные в шаблоне договора
+type ContractData struct {
+	Contract       models.Contract
+	Buyer          models.Organization
+	Supplier       string
+	BuyerSigner    string
+	SupplierSigner string
+}
+
+// ContractRenderer формирует PDF договора. Текст готовится шаблоном, а затем раскладывается по страницам:
+// строки с префиксом "# " и "## " выводятся как заголовки
+type ContractRenderer struct {
+	font []byte
+}
+
+// NewContractRenderer загружает TTF-шрифт с поддержкой кириллицы, которым набирается договор
+func NewContractRenderer(fontPath string) (*ContractRenderer, error) {
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContractRenderer{font: font}, nil
+}
+
+func (r *ContractRenderer) Render(data ContractData) ([]byte, error) {
+	var text bytes.Buffer
+	if err := contractTmpl.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Contract "+data.Contract.ID.String(), true)
+	pdf.SetCreationDate(data.Contract.CreatedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", r.font)
+	pdf.AddPage()
+
+	scanner := bufio.NewScanner(&text)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "# "):
+			pdf.SetFont(fontFamily, "", 16)
+			pdf.MultiCell(0, 9, strings.TrimPrefix(line, "# "), "", "C", false)
+			pdf.Ln(2)
+		case strings.HasPrefix(line, "## "):
+			pdf.Ln(3)
+			pdf.SetFont(fontFamily, "", 13)
+			pdf.MultiCell(0, 7, strings.TrimPrefix(line, "## "), "", "L", false)
+		case strings.TrimSpace(line) == "":
+			pdf.Ln(2)
+		default:
+			pdf.SetFont(fontFamily, "", 11)
+			pdf.MultiCell(0, 6, line, "", "J", false)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,211 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+// Размер буфера подписки. Если клиент не успевает читать события, подписка закрывается,
+// и клиент переподключается с заголовком Last-Event-ID
+const subscriptionBuffer = 64
+
+const reconnectDelay = 5 * time.Second
+
+// Идентификатор события выдаётся при вставке, а видно событие после фиксации транзакции, поэтому событие
+// с меньшим идентификатором может появиться позже события с большим. Пропущенные идентификаторы ожидаются
+// GapTimeout; не заполненный за это время пропуск считается идентификатором отменённой транзакции.
+// maxGaps ограничивает число пропусков, запоминаемых при одном скачке идентификаторов
+const (
+	GapTimeout = time.Minute
+	maxGaps    = 1000
+)
+
+type Subscription struct {
+	C chan *models.Event
+}
+
+// Broker слушает канал Postgres и раздаёт события подписчикам текущего экземпляра сервера
+type Broker struct {
+	db          *gorm.DB
+	connString  string
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	lastID      int64
+	gaps        map[int64]time.Time
+	listening   atomic.Bool
+}
+
+func NewBroker(db *gorm.DB, connString string) *Broker {
+	return &Broker{
+		db:          db,
+		connString:  connString,
+		subscribers: make(map[*Subscription]struct{}),
+		gaps:        make(map[int64]time.Time),
+	}
+}
+
+func (b *Broker) Subscribe() *Subscription {
+	sub := &Subscription{C: make(chan *models.Event, subscriptionBuffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.C)
+	}
+}
+
+// Run слушает уведомления до отмены контекста, переподключаясь при обрыве соединения.
+// После отмены контекста все подписки закрываются, чтобы открытые потоки могли завершиться
+func (b *Broker) Run(ctx context.Context) {
+	defer b.closeAll()
+
+	// События, созданные до запуска, клиенты получают через Last-Event-ID
+	b.db.Model(&models.Event{}).Select("COALESCE(MAX(id), 0)").Scan(&b.lastID)
+
+	for {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Event listener stopped", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.connString)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+
+	b.listening.Store(true)
+	defer b.listening.Store(false)
+
+	// Рассылка событий, пропущенных за время переподключения
+	b.catchUp()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, gap := b.gaps[id]; id <= b.lastID && !gap {
+			continue
+		}
+
+		b.catchUp()
+	}
+}
+
+// Check сообщает об ошибке, если слушатель не подключён к каналу и потоки событий не получают новых событий
+func (b *Broker) Check(context.Context) error {
+	if !b.listening.Load() {
+		return errors.New("event listener is not connected")
+	}
+	return nil
+}
+
+// catchUp загружает события после последнего разосланного и события с пропущенными ранее идентификаторами
+// и раздаёт их подписчикам. Уведомления могут приходить не по порядку идентификаторов, поэтому события
+// читаются из таблицы
+func (b *Broker) catchUp() {
+	b.pruneGaps()
+
+	query := b.db.Where("id > ?", b.lastID)
+	if len(b.gaps) > 0 {
+		ids := make([]int64, 0, len(b.gaps))
+		for id := range b.gaps {
+			ids = append(ids, id)
+		}
+		query = b.db.Where("id > ? OR id IN ?", b.lastID, ids)
+	}
+
+	var events []models.Event
+	if err := query.Order("id").Find(&events).Error; err != nil {
+		slog.Error("Failed to load events", "after_id", b.lastID, "error", err)
+		return
+	}
+
+	for i := range events {
+		id := events[i].ID
+		if id > b.lastID {
+			b.trackGaps(b.lastID, id)
+			b.lastID = id
+		} else {
+			delete(b.gaps, id)
+		}
+		b.broadcast(&events[i])
+	}
+}
+
+// trackGaps запоминает идентификаторы между from и to, событий с которыми ещё нет
+func (b *Broker) trackGaps(from, to int64) {
+	now := time.Now()
+	for id := max(from+1, to-maxGaps); id < to; id++ {
+		b.gaps[id] = now
+	}
+}
+
+func (b *Broker) pruneGaps() {
+	for id, since := range b.gaps {
+		if time.Since(since) > GapTimeout {
+			delete(b.gaps, id)
+		}
+	}
+}
+
+func (b *Broker) broadcast(event *models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.C <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.C)
+		}
+	}
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.C)
+	}
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+// Channel - канал Postgres, через который экземпляры сервера узнают о новых событиях
+const Channel = "tender_events"
+
+// Publish сохраняет событие и уведомляет всех слушателей через NOTIFY.
+// Внутри транзакции уведомление будет доставлено только после её фиксации.
+func Publish(db *gorm.DB, event *models.Event, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	event.Payload = string(data)
+
+	if err := db.Create(event).Error; err != nil {
+		return err
+	}
+
+	return db.Exec("SELECT pg_notify(?, ?)", Channel, strconv.FormatInt(event.ID, 10)).Error
+}
+
+func TenderStatusChanged(db *gorm.DB, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventTenderStatus,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+	}, map[string]any{"status": tender.Status, "version": tender.Version})
+}
+
+func BidPublished(db *gorm.DB, bid models.Bid) error {
+	var tender models.Tender
+	if err := db.Where("id = ?", bid.TenderID).First(&tender).Error; err != nil {
+		return err
+	}
+
+	return Publish(db, &models.Event{
+		Type:           models.EventBidPublished,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+		BidID:          &bid.ID,
+		BidAuthorID:    &bid.AuthorID,
+	}, map[string]any{"bidId": bid.ID, "version": bid.Version})
+}
+
+func BidWithdrawn(db *gorm.DB, withdrawal models.BidWithdrawal, bid models.Bid, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventBidWithdrawn,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+		BidID:          &bid.ID,
+		BidAuthorID:    &bid.AuthorID,
+	}, map[string]any{"withdrawalId": withdrawal.ID, "version": bid.Version})
+}
+
+func DecisionSubmitted(db *gorm.DB, decision models.Decision, bid models.Bid, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventDecision,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+		BidID:          &bid.ID,
+		BidAuthorID:    &bid.AuthorID,
+	}, map[string]any{"decisionId": decision.ID, "decisionType": decision.DecisionType})
+}
+
+func FeedbackSubmitted(db *gorm.DB, review models.Review, bid models.Bid, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventFeedback,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+		BidID:          &bid.ID,
+		BidAuthorID:    &bid.AuthorID,
+	}, map[string]any{"reviewId": review.ID})
+}
+
+// Цена аукциона рассылается без идентификаторов предложений и их авторов
+func AuctionRoundChanged(db *gorm.DB, round models.AuctionRound, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventAuctionRound,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+	}, map[string]any{"roundId": round.ID, "number": round.Number, "status": round.Status, "bestPrice": round.BestPrice, "endsAt": round.EndsAt})
+}
+
+func AuctionPriceChanged(db *gorm.DB, round models.AuctionRound, tender models.Tender) error {
+	return Publish(db, &models.Event{
+		Type:           models.EventAuctionPrice,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+	}, map[string]any{"roundId": round.ID, "number": round.Number, "bestPrice": round.BestPrice})
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"myapp/models"
+)
+
+type bidResolver struct {
+	b *models.Bid
+}
+
+func (r *bidResolver) ID() graphql.ID          { return graphql.ID(r.b.ID.String()) }
+func (r *bidResolver) Name() string            { return r.b.Name }
+func (r *bidResolver) Description() string     { return r.b.Description }
+func (r *bidResolver) Status() string          { return string(r.b.Status) }
+func (r *bidResolver) AuthorType() string      { return string(r.b.AuthorType) }
+func (r *bidResolver) Price() *float64         { return r.b.Price }
+func (r *bidResolver) Version() int32          { return int32(r.b.Version) }
+func (r *bidResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.b.CreatedAt} }
+
+func (r *bidResolver) Author(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.b.AuthorID)
+}
+
+// Tender возвращает null без ошибки, если тендер недоступен пользователю: например, автору предложения
+// после закрытия тендера
+func (r *bidResolver) Tender(ctx context.Context) (*tenderResolver, error) {
+	s := stateFrom(ctx)
+
+	tender, err := s.loaders.tenders.Load(ctx, r.b.TenderID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve tenders")
+	}
+	if tender == nil || !s.viewer.canViewTender(tender) {
+		return nil, nil
+	}
+	return &tenderResolver{tender}, nil
+}
+
+func (r *bidResolver) Lots(ctx context.Context) ([]*bidLotResolver, error) {
+	lots, err := stateFrom(ctx).loaders.bidLots.Load(ctx, r.b.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve lots")
+	}
+
+	resolvers := make([]*bidLotResolver, len(lots))
+	for i, l := range lots {
+		resolvers[i] = &bidLotResolver{l}
+	}
+	return resolvers, nil
+}
+
+func (r *bidResolver) Decisions(ctx context.Context) ([]*decisionResolver, error) {
+	decisions, err := stateFrom(ctx).loaders.bidDecisions.Load(ctx, r.b.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve decisions")
+	}
+
+	resolvers := make([]*decisionResolver, len(decisions))
+	for i, d := range decisions {
+		resolvers[i] = &decisionResolver{d}
+	}
+	return resolvers, nil
+}
+
+func (r *bidResolver) Reviews(ctx context.Context) ([]*reviewResolver, error) {
+	reviews, err := stateFrom(ctx).loaders.bidReviews.Load(ctx, r.b.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve reviews")
+	}
+	return reviewResolvers(reviews), nil
+}
+
+// History доступна только владельцам предложения, как откат версии в PUT /api/bids/{bidId}/rollback/{version}
+func (r *bidResolver) History(ctx context.Context) (*[]*bidVersionResolver, error) {
+	s := stateFrom(ctx)
+	if !s.viewer.isBidOwner(r.b) {
+		return nil, forbidden("User is not authorized for this bid")
+	}
+
+	versions, err := s.loaders.bidHistory.Load(ctx, r.b.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+
+	resolvers := make([]*bidVersionResolver, len(versions))
+	for i, h := range versions {
+		resolvers[i] = &bidVersionResolver{h}
+	}
+	return &resolvers, nil
+}
+
+type bidLotResolver struct {
+	l *models.BidLot
+}
+
+func (r *bidLotResolver) LotID() graphql.ID { return graphql.ID(r.l.LotID.String()) }
+func (r *bidLotResolver) Price() float64    { return r.l.Price }
+func (r *bidLotResolver) Status() string    { return string(r.l.Status) }
+
+type bidVersionResolver struct {
+	h *models.BidHistory
+}
+
+func (r *bidVersionResolver) Version() int32          { return int32(r.h.Version) }
+func (r *bidVersionResolver) Name() string            { return r.h.Name }
+func (r *bidVersionResolver) Description() string     { return r.h.Description }
+func (r *bidVersionResolver) Status() string          { return string(r.h.Status) }
+func (r *bidVersionResolver) Price() *float64         { return r.h.Price }
+func (r *bidVersionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.h.CreatedAt} }
+
+type decisionResolver struct {
+	d *models.Decision
+}
+
+func (r *decisionResolver) ID() graphql.ID          { return graphql.ID(r.d.ID.String()) }
+func (r *decisionResolver) BidID() graphql.ID       { return graphql.ID(r.d.BidID.String()) }
+func (r *decisionResolver) LotID() *graphql.ID      { return optionalID(r.d.LotID) }
+func (r *decisionResolver) DecisionType() string    { return string(r.d.DecisionType) }
+func (r *decisionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.d.CreatedAt} }
+
+func (r *decisionResolver) Author(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.d.AuthorID)
+}
@@ -0,0 +1,158 @@
+// Package graph - GraphQL API для чтения тендеров вместе с предложениями, решениями, отзывами и историей
+// за один запрос. Связанные записи загружаются пакетами через dataloader, права доступа проверяются
+// по тем же правилам, что и в контроллерах REST API
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+	"myapp/config"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Ограничения запроса: глубина вложенности и число полей, которые вычисляются одновременно
+const (
+	maxDepth       = 10
+	maxParallelism = 20
+)
+
+// QueryRequest - тело запроса POST /api/graphql
+type QueryRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type Handler struct {
+	DB         *gorm.DB
+	Pagination config.Pagination
+	schema     *graphql.Schema
+}
+
+// NewHandler разбирает схему и проверяет, что у каждого её поля есть резолвер
+func NewHandler(db *gorm.DB, pagination config.Pagination) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &queryResolver{},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+
+	return &Handler{DB: db, Pagination: pagination, schema: schema}
+}
+
+// Query выполняет запрос GraphQL. Ошибки отдельных полей возвращаются в списке errors с кодом в extensions,
+// а неизвестный пользователь отклоняется до выполнения запроса, как в REST API
+func (h *Handler) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req QueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
+		slog.DebugContext(ctx, "Request failed", "reason", "Invalid request body", "error", err)
+		return
+	}
+
+	db := h.DB.WithContext(ctx)
+
+	v, err := loadViewer(db, c.Query("username"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{"reason": "User does not exist"})
+		slog.DebugContext(ctx, "Request failed", "reason", "User does not exist", "error", err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"reason": "Failed to retrieve user"})
+		slog.ErrorContext(ctx, "Request failed", "reason", "Failed to retrieve user", "error", err)
+		return
+	}
+
+	ctx = context.WithValue(ctx, stateKey{}, &state{
+		db:         db,
+		viewer:     v,
+		loaders:    newLoaders(db),
+		pagination: h.Pagination,
+	})
+
+	c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+type stateKey struct{}
+
+// state - данные одного запроса: пользователь и загрузчики, которые кешируют записи только в его пределах
+type state struct {
+	db         *gorm.DB
+	viewer     *viewer
+	loaders    *loaders
+	pagination config.Pagination
+}
+
+func stateFrom(ctx context.Context) *state {
+	return ctx.Value(stateKey{}).(*state)
+}
+
+// page проверяет параметры страницы так же, как контроллеры REST API: limit по умолчанию и его предел
+// берутся из настроек pagination
+func (s *state) page(limit, offset *int32) (int, int, error) {
+	l, o := s.pagination.DefaultLimit, 0
+	if limit != nil {
+		if *limit <= 0 {
+			return 0, 0, badRequest("Invalid limit parameter")
+		}
+		l = int(*limit)
+	}
+	if offset != nil {
+		if *offset < 0 {
+			return 0, 0, badRequest("Invalid offset parameter")
+		}
+		o = int(*offset)
+	}
+
+	return min(l, s.pagination.MaxLimit), o, nil
+}
+
+// Error - ошибка поля; причина совпадает с reason в REST API, а код передаётся в extensions
+type Error struct {
+	Reason string
+	Code   string
+}
+
+const (
+	CodeBadRequest = "BAD_REQUEST"
+	CodeForbidden  = "FORBIDDEN"
+	CodeNotFound   = "NOT_FOUND"
+	CodeInternal   = "INTERNAL"
+)
+
+func (e *Error) Error() string {
+	return e.Reason
+}
+
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+func badRequest(reason string) error {
+	return &Error{Reason: reason, Code: CodeBadRequest}
+}
+
+func forbidden(reason string) error {
+	return &Error{Reason: reason, Code: CodeForbidden}
+}
+
+func notFound(reason string) error {
+	return &Error{Reason: reason, Code: CodeNotFound}
+}
+
+// internal записывает ошибку базы данных в журнал и возвращает клиенту только причину
+func internal(ctx context.Context, err error, reason string) error {
+	slog.ErrorContext(ctx, "Request failed", "reason", reason, "error", err)
+	return &Error{Reason: reason, Code: CodeInternal}
+}
@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+// Время, в течение которого загрузчик собирает ключи от параллельно вычисляемых полей в один запрос
+const batchWait = 2 * time.Millisecond
+
+// page - ключ загрузчика страницы дочерних записей: родитель и параметры страницы
+type page struct {
+	parent uuid.UUID
+	limit  int
+	offset int
+}
+
+// loaders - загрузчики связанных записей одного запроса. Каждый загрузчик объединяет ключи,
+// запрошенные полями разных записей, в один запрос к базе данных и кеширует результат
+type loaders struct {
+	employees     *dataloader.Loader[uuid.UUID, *models.Employee]
+	organizations *dataloader.Loader[uuid.UUID, *models.Organization]
+	tenders       *dataloader.Loader[uuid.UUID, *models.Tender]
+
+	// Вскрыты ли предложения тендера вручную и число поданных предложений
+	openings  *dataloader.Loader[uuid.UUID, bool]
+	bidCounts *dataloader.Loader[uuid.UUID, int64]
+
+	tenderLots    *dataloader.Loader[uuid.UUID, []*models.TenderLot]
+	tenderHistory *dataloader.Loader[uuid.UUID, []*models.TenderHistory]
+	tenderBids    *dataloader.Loader[page, []*models.Bid]
+	tenderReviews *dataloader.Loader[page, []*models.Review]
+
+	bidLots      *dataloader.Loader[uuid.UUID, []*models.BidLot]
+	bidDecisions *dataloader.Loader[uuid.UUID, []*models.Decision]
+	bidReviews   *dataloader.Loader[uuid.UUID, []*models.Review]
+	bidHistory   *dataloader.Loader[uuid.UUID, []*models.BidHistory]
+
+	// Ответственные лица организации и организации сотрудника
+	orgResponsibles *dataloader.Loader[uuid.UUID, []uuid.UUID]
+	employeeOrgs    *dataloader.Loader[uuid.UUID, []uuid.UUID]
+}
+
+func newLoaders(db *gorm.DB) *loaders {
+	return &loaders{
+		employees:     byID(db, func(e *models.Employee) uuid.UUID { return e.ID }),
+		organizations: byID(db, func(o *models.Organization) uuid.UUID { return o.ID }),
+		tenders:       byID(db, func(t *models.Tender) uuid.UUID { return t.ID }),
+
+		openings: batch(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+			var opened []uuid.UUID
+			err := db.Model(&models.TenderOpening{}).Where("tender_id IN ?", ids).Pluck("tender_id", &opened).Error
+			found := make(map[uuid.UUID]bool, len(opened))
+			for _, id := range opened {
+				found[id] = true
+			}
+			return found, err
+		}),
+		bidCounts: batch(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			var counts []struct {
+				TenderID uuid.UUID
+				Count    int64
+			}
+			err := db.Model(&models.Bid{}).Select("tender_id, COUNT(*) AS count").
+				Where("tender_id IN ? AND status = ?", ids, models.BidPublished).Group("tender_id").Scan(&counts).Error
+			found := make(map[uuid.UUID]int64, len(counts))
+			for _, c := range counts {
+				found[c.TenderID] = c.Count
+			}
+			return found, err
+		}),
+
+		tenderLots: grouped(func(ids []uuid.UUID) ([]models.TenderLot, error) {
+			var lots []models.TenderLot
+			err := db.Where("tender_id IN ?", ids).Order("created_at").Find(&lots).Error
+			return lots, err
+		}, func(l *models.TenderLot) uuid.UUID { return l.TenderID }),
+		tenderHistory: grouped(func(ids []uuid.UUID) ([]models.TenderHistory, error) {
+			var versions []models.TenderHistory
+			err := db.Where("tender_id IN ?", ids).Order("version DESC").Find(&versions).Error
+			return versions, err
+		}, func(h *models.TenderHistory) uuid.UUID { return h.TenderID }),
+		tenderBids:    tenderBids(db),
+		tenderReviews: tenderReviews(db),
+
+		bidLots: grouped(func(ids []uuid.UUID) ([]models.BidLot, error) {
+			var lots []models.BidLot
+			err := db.Where("bid_id IN ?", ids).Find(&lots).Error
+			return lots, err
+		}, func(l *models.BidLot) uuid.UUID { return l.BidID }),
+		bidDecisions: grouped(func(ids []uuid.UUID) ([]models.Decision, error) {
+			var decisions []models.Decision
+			err := db.Where("bid_id IN ?", ids).Order("created_at").Find(&decisions).Error
+			return decisions, err
+		}, func(d *models.Decision) uuid.UUID { return d.BidID }),
+		bidReviews: grouped(func(ids []uuid.UUID) ([]models.Review, error) {
+			var reviews []models.Review
+			err := db.Preload("Reply").Where("bid_id IN ?", ids).Order("created_at DESC").Find(&reviews).Error
+			return reviews, err
+		}, func(r *models.Review) uuid.UUID { return r.BidID }),
+		bidHistory: grouped(func(ids []uuid.UUID) ([]models.BidHistory, error) {
+			var versions []models.BidHistory
+			err := db.Where("bid_id IN ?", ids).Order("version DESC").Find(&versions).Error
+			return versions, err
+		}, func(h *models.BidHistory) uuid.UUID { return h.BidID }),
+
+		orgResponsibles: memberships(db, "organization_id", "user_id"),
+		employeeOrgs:    memberships(db, "user_id", "organization_id"),
+	}
+}
+
+// batch создаёт загрузчик из функции, которая получает значения для всех ключей пакета одним запросом.
+// Ключи, которых нет в результате, получают нулевое значение
+func batch[V any](fetch func(ctx context.Context, keys []uuid.UUID) (map[uuid.UUID]V, error)) *dataloader.Loader[uuid.UUID, V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []uuid.UUID) []*dataloader.Result[V] {
+		found, err := fetch(ctx, keys)
+
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[V]{Data: found[key], Error: err}
+		}
+		return results
+	}, dataloader.WithWait[uuid.UUID, V](batchWait))
+}
+
+// byID загружает записи по первичному ключу; для несуществующей записи возвращается nil
+func byID[T any](db *gorm.DB, id func(*T) uuid.UUID) *dataloader.Loader[uuid.UUID, *T] {
+	return batch(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*T, error) {
+		var rows []T
+		err := db.Where("id IN ?", ids).Find(&rows).Error
+		return index(rows, id), err
+	})
+}
+
+// grouped загружает дочерние записи всех родителей пакета и раскладывает их по родителям с сохранением порядка
+func grouped[T any](fetch func(ids []uuid.UUID) ([]T, error), parent func(*T) uuid.UUID) *dataloader.Loader[uuid.UUID, []*T] {
+	return batch(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*T, error) {
+		rows, err := fetch(ids)
+		return group(rows, parent), err
+	})
+}
+
+// paged загружает страницы дочерних записей. Ключи с одинаковыми параметрами страницы загружаются
+// одним запросом, который нумерует записи внутри каждого родителя
+func paged[T any](fetch func(ids []uuid.UUID, limit, offset int) (map[uuid.UUID][]*T, error)) *dataloader.Loader[page, []*T] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []page) []*dataloader.Result[[]*T] {
+		type window struct{ limit, offset int }
+		parents := map[window][]uuid.UUID{}
+		for _, key := range keys {
+			w := window{key.limit, key.offset}
+			parents[w] = append(parents[w], key.parent)
+		}
+
+		pages := map[page][]*T{}
+		errs := map[window]error{}
+		for w, ids := range parents {
+			found, err := fetch(ids, w.limit, w.offset)
+			errs[w] = err
+			for id, items := range found {
+				pages[page{id, w.limit, w.offset}] = items
+			}
+		}
+
+		results := make([]*dataloader.Result[[]*T], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[[]*T]{Data: pages[key], Error: errs[window{key.limit, key.offset}]}
+		}
+		return results
+	}, dataloader.WithWait[page, []*T](batchWait))
+}
+
+// memberships загружает связи из organization_responsibles: по организации - её ответственных лиц,
+// по сотруднику - его организации
+func memberships(db *gorm.DB, key, value string) *dataloader.Loader[uuid.UUID, []uuid.UUID] {
+	return batch(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+		var links []struct {
+			ParentID uuid.UUID
+			ChildID  uuid.UUID
+		}
+		err := db.Model(&models.OrganizationResponsible{}).Select(key+" AS parent_id, "+value+" AS child_id").
+			Where(key+" IN ?", ids).Scan(&links).Error
+
+		found := map[uuid.UUID][]uuid.UUID{}
+		for _, l := range links {
+			found[l.ParentID] = append(found[l.ParentID], l.ChildID)
+		}
+		return found, err
+	})
+}
+
+// tenderBids загружает страницы поданных и отозванных предложений тендеров в порядке GET /api/bids/{tenderId}/list
+func tenderBids(db *gorm.DB) *dataloader.Loader[page, []*models.Bid] {
+	return paged(func(tenderIDs []uuid.UUID, limit, offset int) (map[uuid.UUID][]*models.Bid, error) {
+		numbered := db.Model(&models.Bid{}).
+			Select("bids.*, ROW_NUMBER() OVER (PARTITION BY tender_id ORDER BY name, id) AS seq").
+			Where("tender_id IN ? AND status IN ?", tenderIDs, []models.BidStatus{models.BidPublished, models.BidWithdrawn})
+
+		var bids []models.Bid
+		err := db.Table("(?) AS bids", numbered).Where("seq > ? AND seq <= ?", offset, offset+limit).
+			Order("seq").Find(&bids).Error
+		return group(bids, func(b *models.Bid) uuid.UUID { return b.TenderID }), err
+	})
+}
+
+// tenderReviews загружает страницы отзывов по предложениям тендеров, новые первыми, как GET /api/bids/{tenderId}/reviews.
+// Сначала выбираются идентификаторы отзывов страницы, затем сами отзывы вместе с ответами
+func tenderReviews(db *gorm.DB) *dataloader.Loader[page, []*models.Review] {
+	return paged(func(tenderIDs []uuid.UUID, limit, offset int) (map[uuid.UUID][]*models.Review, error) {
+		numbered := db.Table("reviews").
+			Select("reviews.id, bids.tender_id, ROW_NUMBER() OVER (PARTITION BY bids.tender_id ORDER BY reviews.created_at DESC, reviews.id) AS seq").
+			Joins("JOIN bids ON bids.id = reviews.bid_id").
+			Where("bids.tender_id IN ?", tenderIDs)
+
+		var refs []struct {
+			ID       uuid.UUID
+			TenderID uuid.UUID
+		}
+		if err := db.Table("(?) AS refs", numbered).Select("id, tender_id").
+			Where("seq > ? AND seq <= ?", offset, offset+limit).Order("seq").Scan(&refs).Error; err != nil {
+			return nil, err
+		}
+		if len(refs) == 0 {
+			return nil, nil
+		}
+
+		ids := make([]uuid.UUID, len(refs))
+		for i, r := range refs {
+			ids[i] = r.ID
+		}
+		var reviews []models.Review
+		if err := db.Preload("Reply").Where("id IN ?", ids).Find(&reviews).Error; err != nil {
+			return nil, err
+		}
+
+		byID := index(reviews, func(r *models.Review) uuid.UUID { return r.ID })
+		found := map[uuid.UUID][]*models.Review{}
+		for _, r := range refs {
+			if review := byID[r.ID]; review != nil {
+				found[r.TenderID] = append(found[r.TenderID], review)
+			}
+		}
+		return found, nil
+	})
+}
+
+func index[T any](rows []T, id func(*T) uuid.UUID) map[uuid.UUID]*T {
+	found := make(map[uuid.UUID]*T, len(rows))
+	for i := range rows {
+		found[id(&rows[i])] = &rows[i]
+	}
+	return found
+}
+
+func group[T any](rows []T, parent func(*T) uuid.UUID) map[uuid.UUID][]*T {
+	found := map[uuid.UUID][]*T{}
+	for i := range rows {
+		id := parent(&rows[i])
+		found[id] = append(found[id], &rows[i])
+	}
+	return found
+}
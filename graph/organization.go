@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"myapp/models"
+)
+
+type organizationResolver struct {
+	o *models.Organization
+}
+
+func (r *organizationResolver) ID() graphql.ID          { return graphql.ID(r.o.ID.String()) }
+func (r *organizationResolver) Name() string            { return r.o.Name }
+func (r *organizationResolver) Description() string     { return r.o.Description }
+func (r *organizationResolver) Type() string            { return string(r.o.Type) }
+func (r *organizationResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.o.CreatedAt} }
+
+func (r *organizationResolver) Responsibles(ctx context.Context) (*[]*employeeResolver, error) {
+	s := stateFrom(ctx)
+	if !s.viewer.isResponsible(r.o.ID) {
+		return nil, forbidden("User is not authorized for this organization")
+	}
+
+	ids, err := s.loaders.orgResponsibles.Load(ctx, r.o.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve organization")
+	}
+
+	employees, errs := s.loaders.employees.LoadMany(ctx, ids)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve organization")
+	}
+
+	resolvers := make([]*employeeResolver, 0, len(employees))
+	for _, e := range employees {
+		if e != nil {
+			resolvers = append(resolvers, &employeeResolver{e})
+		}
+	}
+	return &resolvers, nil
+}
+
+// employeeResolver не раскрывает email: адрес виден только самому пользователю в настройках уведомлений
+type employeeResolver struct {
+	e *models.Employee
+}
+
+func loadEmployee(ctx context.Context, id uuid.UUID) (*employeeResolver, error) {
+	employee, err := stateFrom(ctx).loaders.employees.Load(ctx, id)()
+	if err != nil || employee == nil {
+		return nil, internal(ctx, err, "Failed to retrieve user")
+	}
+	return &employeeResolver{employee}, nil
+}
+
+func (r *employeeResolver) ID() graphql.ID    { return graphql.ID(r.e.ID.String()) }
+func (r *employeeResolver) Username() string  { return r.e.Username }
+func (r *employeeResolver) FirstName() string { return r.e.FirstName }
+func (r *employeeResolver) LastName() string  { return r.e.LastName }
+
+// Organizations возвращает только организации, в которых ответственным лицом является и пользователь запроса
+func (r *employeeResolver) Organizations(ctx context.Context) ([]*organizationResolver, error) {
+	s := stateFrom(ctx)
+
+	ids, err := s.loaders.employeeOrgs.Load(ctx, r.e.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve organizations")
+	}
+
+	shared := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if s.viewer.isResponsible(id) {
+			shared = append(shared, id)
+		}
+	}
+
+	organizations, errs := s.loaders.organizations.LoadMany(ctx, shared)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve organizations")
+	}
+
+	resolvers := make([]*organizationResolver, 0, len(organizations))
+	for _, o := range organizations {
+		if o != nil {
+			resolvers = append(resolvers, &organizationResolver{o})
+		}
+	}
+	return resolvers, nil
+}
+
+func optionalID(id *uuid.UUID) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	v := graphql.ID(id.String())
+	return &v
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+type queryResolver struct{}
+
+type pageArgs struct {
+	Limit  *int32
+	Offset *int32
+}
+
+func (queryResolver) Me(ctx context.Context) *employeeResolver {
+	v := stateFrom(ctx).viewer
+	if v.employee == nil {
+		return nil
+	}
+	return &employeeResolver{v.employee}
+}
+
+func (queryResolver) Tender(ctx context.Context, args struct{ ID graphql.ID }) (*tenderResolver, error) {
+	s := stateFrom(ctx)
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, notFound("Tender not found")
+	}
+
+	tender, err := s.loaders.tenders.Load(ctx, id)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve tenders")
+	}
+	if tender == nil {
+		return nil, notFound("Tender not found")
+	}
+
+	if !s.viewer.canViewTender(tender) {
+		return nil, forbidden("User is not authorized for this tender")
+	}
+
+	return &tenderResolver{tender}, nil
+}
+
+func (queryResolver) Tenders(ctx context.Context, args struct {
+	ServiceTypes *[]string
+	Limit        *int32
+	Offset       *int32
+}) ([]*tenderResolver, error) {
+	s := stateFrom(ctx)
+
+	limit, offset, err := s.page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Как в GET /api/tenders: только опубликованные тендеры, закрытые - для приглашённых и ответственных лиц
+	query := s.db.Limit(limit).Offset(offset).Order("name").Where("status = ?", models.Published)
+	if s.viewer.employee == nil {
+		query = query.Where("visibility = ?", models.VisibilityPublic)
+	} else {
+		query = query.Where(s.db.Where("visibility = ?", models.VisibilityPublic).
+			Or("id IN ?", keys(s.viewer.invited)).
+			Or("organization_id IN ?", keys(s.viewer.orgs)))
+	}
+	if args.ServiceTypes != nil && len(*args.ServiceTypes) > 0 {
+		query = query.Where("service_type IN ?", *args.ServiceTypes)
+	}
+
+	return s.findTenders(ctx, query)
+}
+
+func (queryResolver) MyTenders(ctx context.Context, args pageArgs) ([]*tenderResolver, error) {
+	s := stateFrom(ctx)
+	if s.viewer.employee == nil {
+		return nil, badRequest("Missing required parameter(s)")
+	}
+
+	limit, offset, err := s.page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Limit(limit).Offset(offset).Order("name").Where("organization_id IN ?", keys(s.viewer.orgs))
+	return s.findTenders(ctx, query)
+}
+
+func (queryResolver) Bid(ctx context.Context, args struct{ ID graphql.ID }) (*bidResolver, error) {
+	s := stateFrom(ctx)
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, notFound("Bid not found")
+	}
+
+	var bid models.Bid
+	if err := s.db.Where("id = ?", id).First(&bid).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFound("Bid not found")
+		}
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+
+	ok, err := s.viewer.canViewBid(ctx, &bid)
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+	if !ok {
+		return nil, forbidden("User is not authorized for this bid")
+	}
+
+	return &bidResolver{&bid}, nil
+}
+
+func (queryResolver) MyBids(ctx context.Context, args pageArgs) ([]*bidResolver, error) {
+	s := stateFrom(ctx)
+	if s.viewer.employee == nil {
+		return nil, badRequest("Missing required parameter(s)")
+	}
+
+	limit, offset, err := s.page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var bids []models.Bid
+	if err := s.db.Limit(limit).Offset(offset).Order("name").Where("author_id = ?", s.viewer.employee.ID).Find(&bids).Error; err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+
+	resolvers := make([]*bidResolver, len(bids))
+	for i := range bids {
+		resolvers[i] = &bidResolver{&bids[i]}
+	}
+	return resolvers, nil
+}
+
+// findTenders выполняет запрос списка тендеров и кладёт их в загрузчик, чтобы поле tender
+// предложений этих тендеров не запрашивало их повторно
+func (s *state) findTenders(ctx context.Context, query *gorm.DB) ([]*tenderResolver, error) {
+	var tenders []models.Tender
+	if err := query.Find(&tenders).Error; err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve tenders")
+	}
+
+	resolvers := make([]*tenderResolver, len(tenders))
+	for i := range tenders {
+		s.loaders.tenders.Prime(ctx, tenders[i].ID, &tenders[i])
+		resolvers[i] = &tenderResolver{&tenders[i]}
+	}
+	return resolvers, nil
+}
+
+func keys(set map[uuid.UUID]bool) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"myapp/models"
+)
+
+type reviewResolver struct {
+	r *models.Review
+}
+
+func reviewResolvers(reviews []*models.Review) []*reviewResolver {
+	resolvers := make([]*reviewResolver, len(reviews))
+	for i, r := range reviews {
+		resolvers[i] = &reviewResolver{r}
+	}
+	return resolvers
+}
+
+func (r *reviewResolver) ID() graphql.ID          { return graphql.ID(r.r.ID.String()) }
+func (r *reviewResolver) BidID() graphql.ID       { return graphql.ID(r.r.BidID.String()) }
+func (r *reviewResolver) Description() string     { return r.r.Description }
+func (r *reviewResolver) Quality() *int32         { return optionalInt(r.r.Quality) }
+func (r *reviewResolver) Timeliness() *int32      { return optionalInt(r.r.Timeliness) }
+func (r *reviewResolver) Communication() *int32   { return optionalInt(r.r.Communication) }
+func (r *reviewResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.r.CreatedAt} }
+func (r *reviewResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.r.UpdatedAt} }
+
+func (r *reviewResolver) Reviewer(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.r.ReviewerID)
+}
+
+func (r *reviewResolver) BidAuthor(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.r.BidAuthorID)
+}
+
+func (r *reviewResolver) Reply() *reviewReplyResolver {
+	if r.r.Reply == nil {
+		return nil
+	}
+	return &reviewReplyResolver{r.r.Reply}
+}
+
+type reviewReplyResolver struct {
+	r *models.ReviewReply
+}
+
+func (r *reviewReplyResolver) ID() graphql.ID          { return graphql.ID(r.r.ID.String()) }
+func (r *reviewReplyResolver) Text() string            { return r.r.Text }
+func (r *reviewReplyResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.r.CreatedAt} }
+
+func (r *reviewReplyResolver) Author(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.r.AuthorID)
+}
+
+func optionalInt(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"myapp/models"
+)
+
+type tenderResolver struct {
+	t *models.Tender
+}
+
+func (r *tenderResolver) ID() graphql.ID          { return graphql.ID(r.t.ID.String()) }
+func (r *tenderResolver) Name() string            { return r.t.Name }
+func (r *tenderResolver) Description() string     { return r.t.Description }
+func (r *tenderResolver) ServiceType() string     { return string(r.t.ServiceType) }
+func (r *tenderResolver) Status() string          { return string(r.t.Status) }
+func (r *tenderResolver) Version() int32          { return int32(r.t.Version) }
+func (r *tenderResolver) Sealed() bool            { return r.t.Sealed }
+func (r *tenderResolver) Deadline() *graphql.Time { return optionalTime(r.t.Deadline) }
+func (r *tenderResolver) AuctionMode() bool       { return r.t.AuctionMode }
+func (r *tenderResolver) Visibility() string      { return string(r.t.Visibility) }
+func (r *tenderResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.t.CreatedAt} }
+
+func (r *tenderResolver) Organization(ctx context.Context) (*organizationResolver, error) {
+	organization, err := stateFrom(ctx).loaders.organizations.Load(ctx, r.t.OrganizationID)()
+	if err != nil || organization == nil {
+		return nil, internal(ctx, err, "Failed to retrieve organization")
+	}
+	return &organizationResolver{organization}, nil
+}
+
+func (r *tenderResolver) Lots(ctx context.Context) ([]*lotResolver, error) {
+	lots, err := stateFrom(ctx).loaders.tenderLots.Load(ctx, r.t.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve lots")
+	}
+
+	resolvers := make([]*lotResolver, len(lots))
+	for i, l := range lots {
+		resolvers[i] = &lotResolver{l}
+	}
+	return resolvers, nil
+}
+
+// Bids повторяет GET /api/bids/{tenderId}/list: пока предложения запечатаны, возвращается только их количество
+func (r *tenderResolver) Bids(ctx context.Context, args pageArgs) (*tenderBidsResolver, error) {
+	s := stateFrom(ctx)
+
+	limit, offset, err := s.page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.viewer.isResponsible(r.t.OrganizationID) {
+		return nil, forbidden("User is not authorized for this organization")
+	}
+
+	sealed, err := bidsSealed(ctx, r.t)
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+
+	if sealed {
+		count, err := s.loaders.bidCounts.Load(ctx, r.t.ID)()
+		if err != nil {
+			return nil, internal(ctx, err, "Failed to retrieve bids")
+		}
+
+		c := int32(count)
+		return &tenderBidsResolver{sealed: true, count: &c}, nil
+	}
+
+	bids, err := s.loaders.tenderBids.Load(ctx, page{r.t.ID, limit, offset})()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve bids")
+	}
+
+	nodes := make([]*bidResolver, len(bids))
+	for i, b := range bids {
+		nodes[i] = &bidResolver{b}
+	}
+	return &tenderBidsResolver{nodes: nodes}, nil
+}
+
+func (r *tenderResolver) Reviews(ctx context.Context, args pageArgs) (*[]*reviewResolver, error) {
+	s := stateFrom(ctx)
+
+	limit, offset, err := s.page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.viewer.isResponsible(r.t.OrganizationID) {
+		return nil, forbidden("User is not authorized")
+	}
+
+	reviews, err := s.loaders.tenderReviews.Load(ctx, page{r.t.ID, limit, offset})()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve reviews")
+	}
+
+	resolvers := reviewResolvers(reviews)
+	return &resolvers, nil
+}
+
+func (r *tenderResolver) History(ctx context.Context) (*[]*tenderVersionResolver, error) {
+	s := stateFrom(ctx)
+	if !s.viewer.isResponsible(r.t.OrganizationID) {
+		return nil, forbidden("User is not authorized for this organization")
+	}
+
+	versions, err := s.loaders.tenderHistory.Load(ctx, r.t.ID)()
+	if err != nil {
+		return nil, internal(ctx, err, "Failed to retrieve tenders")
+	}
+
+	resolvers := make([]*tenderVersionResolver, len(versions))
+	for i, h := range versions {
+		resolvers[i] = &tenderVersionResolver{h}
+	}
+	return &resolvers, nil
+}
+
+type tenderBidsResolver struct {
+	sealed bool
+	count  *int32
+	nodes  []*bidResolver
+}
+
+func (r *tenderBidsResolver) Sealed() bool          { return r.sealed }
+func (r *tenderBidsResolver) Count() *int32         { return r.count }
+func (r *tenderBidsResolver) Nodes() []*bidResolver { return r.nodes }
+
+type lotResolver struct {
+	l *models.TenderLot
+}
+
+func (r *lotResolver) ID() graphql.ID           { return graphql.ID(r.l.ID.String()) }
+func (r *lotResolver) Name() string             { return r.l.Name }
+func (r *lotResolver) Description() string      { return r.l.Description }
+func (r *lotResolver) Budget() float64          { return r.l.Budget }
+func (r *lotResolver) Quantity() int32          { return int32(r.l.Quantity) }
+func (r *lotResolver) Status() string           { return string(r.l.Status) }
+func (r *lotResolver) WinnerBidID() *graphql.ID { return optionalID(r.l.WinnerBidID) }
+func (r *lotResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.l.CreatedAt} }
+
+type tenderVersionResolver struct {
+	h *models.TenderHistory
+}
+
+func (r *tenderVersionResolver) Version() int32          { return int32(r.h.Version) }
+func (r *tenderVersionResolver) Name() string            { return r.h.Name }
+func (r *tenderVersionResolver) Description() string     { return r.h.Description }
+func (r *tenderVersionResolver) ServiceType() string     { return string(r.h.ServiceType) }
+func (r *tenderVersionResolver) Status() string          { return string(r.h.Status) }
+func (r *tenderVersionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.h.CreatedAt} }
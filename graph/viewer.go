@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/models"
+)
+
+// viewer - пользователь запроса со всем, что нужно для проверок доступа: организации, в которых он ответственное лицо,
+// коллеги по этим организациям и тендеры, в которые он приглашён. Всё загружается один раз на запрос,
+// поэтому проверки доступа к спискам записей не требуют отдельных запросов к базе данных
+type viewer struct {
+	// employee равен nil для запроса без username
+	employee   *models.Employee
+	orgs       map[uuid.UUID]bool
+	colleagues map[uuid.UUID]bool
+	invited    map[uuid.UUID]bool
+}
+
+func loadViewer(db *gorm.DB, username string) (*viewer, error) {
+	v := &viewer{orgs: map[uuid.UUID]bool{}, colleagues: map[uuid.UUID]bool{}, invited: map[uuid.UUID]bool{}}
+	if username == "" {
+		return v, nil
+	}
+
+	var employee models.Employee
+	if err := db.Where("username = ?", username).First(&employee).Error; err != nil {
+		return nil, err
+	}
+	v.employee = &employee
+
+	var responsibles []models.OrganizationResponsible
+	orgs := db.Table("organization_responsibles").Select("organization_id").Where("user_id = ?", employee.ID)
+	if err := db.Where("organization_id IN (?)", orgs).Find(&responsibles).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range responsibles {
+		v.colleagues[r.UserID] = true
+		if r.UserID == employee.ID {
+			v.orgs[r.OrganizationID] = true
+		}
+	}
+
+	// Приглашения лично и в организации пользователя, как в invitedScope контроллеров
+	var invited []uuid.UUID
+	if err := db.Model(&models.TenderInvitation{}).Where("employee_id = ?", employee.ID).Or("organization_id IN (?)", orgs).
+		Pluck("tender_id", &invited).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range invited {
+		v.invited[id] = true
+	}
+
+	return v, nil
+}
+
+// isResponsible проверяет, что пользователь является ответственным лицом организации
+func (v *viewer) isResponsible(organizationID uuid.UUID) bool {
+	return v.orgs[organizationID]
+}
+
+// canViewTender повторяет canViewTender контроллеров: ответственные лица видят тендер в любом статусе,
+// остальные - только опубликованный, а закрытый - только по приглашению
+func (v *viewer) canViewTender(tender *models.Tender) bool {
+	if tender.Status == models.Published && tender.Visibility != models.VisibilityInviteOnly {
+		return true
+	}
+	if v.isResponsible(tender.OrganizationID) {
+		return true
+	}
+	return tender.Status == models.Published && v.invited[tender.ID]
+}
+
+// isBidOwner повторяет isBidOwner контроллеров: автор предложения или, для предложений от организации,
+// ответственное лицо той же организации
+func (v *viewer) isBidOwner(bid *models.Bid) bool {
+	if v.employee == nil {
+		return false
+	}
+	if bid.AuthorID == v.employee.ID {
+		return true
+	}
+	return bid.AuthorType == models.AuthorOrganization && v.colleagues[bid.AuthorID]
+}
+
+// canViewBid повторяет canViewBid контроллеров: владельцы видят предложение всегда, ответственные лица
+// организации тендера - только поданное или отозванное и не запечатанное
+func (v *viewer) canViewBid(ctx context.Context, bid *models.Bid) (bool, error) {
+	if v.isBidOwner(bid) {
+		return true, nil
+	}
+	if bid.Status != models.BidPublished && bid.Status != models.BidWithdrawn {
+		return false, nil
+	}
+
+	tender, err := stateFrom(ctx).loaders.tenders.Load(ctx, bid.TenderID)()
+	if err != nil || tender == nil || !v.isResponsible(tender.OrganizationID) {
+		return false, err
+	}
+
+	sealed, err := bidsSealed(ctx, tender)
+	return !sealed, err
+}
+
+// bidsSealed повторяет bidsSealed контроллеров: предложения запечатанного тендера скрыты
+// до истечения срока подачи или ручного вскрытия
+func bidsSealed(ctx context.Context, tender *models.Tender) (bool, error) {
+	if !tender.Sealed {
+		return false, nil
+	}
+	if tender.Deadline != nil && !time.Now().Before(*tender.Deadline) {
+		return false, nil
+	}
+
+	opened, err := stateFrom(ctx).loaders.openings.Load(ctx, tender.ID)()
+	return !opened, err
+}
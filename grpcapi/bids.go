@@ -0,0 +1,199 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"myapp/grpcapi/tenderv1"
+	"myapp/models"
+)
+
+type bidService struct {
+	tenderv1.UnimplementedBidServiceServer
+	gw *gateway
+	db *gorm.DB
+}
+
+func bidPath(id, action string) (string, error) {
+	return resourcePath("/api/bids/", "bid_id", id, action)
+}
+
+func (s *bidService) bid(ctx context.Context, r request) (*tenderv1.Bid, error) {
+	data, err := s.gw.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	bid := &tenderv1.Bid{}
+	return bid, decode(data, bid)
+}
+
+func (s *bidService) CreateBid(ctx context.Context, req *tenderv1.CreateBidRequest) (*tenderv1.Bid, error) {
+	// Предложение подаётся от имени пользователя из метаданных: REST API принимает автора из тела запроса,
+	// поэтому идентификатор сверяется с пользователем вызова
+	username := usernameFrom(ctx)
+	var author models.Employee
+	query := s.db.WithContext(ctx).Where("username = ?", username)
+	if req.AuthorId != "" {
+		authorID, err := uuid.Parse(req.AuthorId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid author_id")
+		}
+		query = s.db.WithContext(ctx).Where("id = ?", authorID)
+	}
+
+	if err := query.First(&author).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "User does not exist")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to retrieve user")
+	}
+	if author.Username != username {
+		return nil, status.Error(codes.PermissionDenied, "Author must be the calling user")
+	}
+	req.AuthorId = author.ID.String()
+
+	return s.bid(ctx, request{method: http.MethodPost, path: "/api/bids/new", body: req})
+}
+
+func (s *bidService) ListMyBids(ctx context.Context, req *tenderv1.ListMyBidsRequest) (*tenderv1.ListBidsResponse, error) {
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: "/api/bids/my", query: pageQuery(req.Page)})
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := decodeList[tenderv1.Bid](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListBidsResponse{Bids: bids}, nil
+}
+
+func (s *bidService) ListTenderBids(ctx context.Context, req *tenderv1.ListTenderBidsRequest) (*tenderv1.ListTenderBidsResponse, error) {
+	path, err := resourcePath("/api/bids/", "tender_id", req.TenderId, "/list")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path, query: pageQuery(req.Page)})
+	if err != nil {
+		return nil, err
+	}
+
+	// Для закрытого тендера до вскрытия REST API возвращает вместо списка объект с числом предложений,
+	// поля которого совпадают с полями ответа
+	if len(data) > 0 && data[0] == '{' {
+		resp := &tenderv1.ListTenderBidsResponse{}
+		return resp, decode(data, resp)
+	}
+
+	bids, err := decodeList[tenderv1.Bid](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListTenderBidsResponse{Bids: bids}, nil
+}
+
+func (s *bidService) GetBidStatus(ctx context.Context, req *tenderv1.GetBidStatusRequest) (*tenderv1.BidStatusResponse, error) {
+	path, err := bidPath(req.BidId, "/status")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := decodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.BidStatusResponse{Status: status}, nil
+}
+
+func (s *bidService) UpdateBidStatus(ctx context.Context, req *tenderv1.UpdateBidStatusRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/status")
+	if err != nil {
+		return nil, err
+	}
+	return s.bid(ctx, request{method: http.MethodPut, path: path, query: url.Values{"status": {req.Status}}})
+}
+
+func (s *bidService) EditBid(ctx context.Context, req *tenderv1.EditBidRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/edit")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := req.Changes
+	if changes == nil {
+		changes = &tenderv1.BidChanges{}
+	}
+	return s.bid(ctx, request{method: http.MethodPatch, path: path, body: changes})
+}
+
+func (s *bidService) RollbackBid(ctx context.Context, req *tenderv1.RollbackBidRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/rollback/"+strconv.Itoa(int(req.Version)))
+	if err != nil {
+		return nil, err
+	}
+	return s.bid(ctx, request{method: http.MethodPut, path: path})
+}
+
+func (s *bidService) LowerBidPrice(ctx context.Context, req *tenderv1.LowerBidPriceRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/price")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"price": {strconv.FormatFloat(req.Price, 'f', -1, 64)}}
+	return s.bid(ctx, request{method: http.MethodPut, path: path, query: query})
+}
+
+func (s *bidService) WithdrawBid(ctx context.Context, req *tenderv1.WithdrawBidRequest) (*tenderv1.BidWithdrawal, error) {
+	path, err := bidPath(req.BidId, "/withdraw")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path, body: map[string]string{"reason": req.Reason}})
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawal := &tenderv1.BidWithdrawal{}
+	return withdrawal, decode(data, withdrawal)
+}
+
+func (s *bidService) ResubmitBid(ctx context.Context, req *tenderv1.ResubmitBidRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/resubmit")
+	if err != nil {
+		return nil, err
+	}
+	return s.bid(ctx, request{method: http.MethodPut, path: path})
+}
+
+func (s *bidService) ListWithdrawals(ctx context.Context, req *tenderv1.ListWithdrawalsRequest) (*tenderv1.ListWithdrawalsResponse, error) {
+	path, err := bidPath(req.BidId, "/withdrawals")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals, err := decodeList[tenderv1.BidWithdrawal](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListWithdrawalsResponse{Withdrawals: withdrawals}, nil
+}
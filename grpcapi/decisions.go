@@ -0,0 +1,40 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"myapp/grpcapi/tenderv1"
+)
+
+type decisionService struct {
+	tenderv1.UnimplementedDecisionServiceServer
+	gw *gateway
+}
+
+func (s *decisionService) SubmitDecision(ctx context.Context, req *tenderv1.SubmitDecisionRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/submit_decision")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"decision": {req.Decision}}
+	if req.LotId != "" {
+		if err := uuid.Validate(req.LotId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid lot_id")
+		}
+		query.Set("lotId", req.LotId)
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path, query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	bid := &tenderv1.Bid{}
+	return bid, decode(data, bid)
+}
@@ -0,0 +1,221 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"myapp/grpcapi/tenderv1"
+)
+
+// Метаданные, которые передаются маршруту как одноимённые заголовки: идентификатор запроса и контекст трассировки
+var forwardedMetadata = []string{"x-request-id", "traceparent", "tracestate"}
+
+var (
+	marshalOptions   = protojson.MarshalOptions{}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// gateway выполняет вызов gRPC как запрос к маршрутизатору REST API без сетевого соединения. Запрос собирается
+// из сообщения и проходит все промежуточные обработчики маршрутизатора (идентификатор запроса, журнал, метрики,
+// трассировку) и контроллер, а ответ декодируется в сообщение gRPC. Так проверки доступа и переходы состояний
+// реализованы один раз для обоих API. Цена - лишнее кодирование в JSON и зависимость от имён полей ответов REST API:
+// поле, переименованное в ответе, без ошибки пропадёт из ответа gRPC. Статус ответа с ошибкой переводит grpcCode
+type gateway struct {
+	handler http.Handler
+}
+
+// request - запрос к REST API. Параметр username подставляется из метаданных вызова
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	// body - тело запроса; сообщения protobuf кодируются в JSON с теми же именами полей, что и в REST API
+	body any
+}
+
+func (g *gateway) do(ctx context.Context, r request) ([]byte, error) {
+	query := r.query
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("username", usernameFrom(ctx))
+
+	var body io.Reader = http.NoBody
+	if r.body != nil {
+		var data []byte
+		var err error
+		if m, ok := r.body.(proto.Message); ok {
+			data, err = marshalOptions.Marshal(m)
+		} else {
+			data, err = json.Marshal(r.body)
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, (&url.URL{Path: r.path, RawQuery: query.Encode()}).String(), body)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range forwardedMetadata {
+		if values := md.Get(key); len(values) > 0 {
+			req.Header.Set(key, values[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	resp := &responseBuffer{header: http.Header{}, status: http.StatusOK}
+	g.handler.ServeHTTP(resp, req)
+
+	if resp.status >= http.StatusMultipleChoices {
+		return nil, statusError(resp.status, resp.body.Bytes())
+	}
+	return resp.body.Bytes(), nil
+}
+
+// statusError переводит ответ REST API с ошибкой в статус gRPC; причина из поля reason становится сообщением
+func statusError(httpStatus int, body []byte) error {
+	var payload struct {
+		Reason string `json:"reason"`
+		Error  string `json:"error"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	message := payload.Reason
+	if message == "" {
+		message = payload.Error
+	}
+	if message == "" {
+		message = http.StatusText(httpStatus)
+	}
+
+	return status.Error(grpcCode(httpStatus), message)
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
+
+// decode читает ответ REST API в сообщение; поля, которых нет в сообщении, пропускаются
+func decode(data []byte, m proto.Message) error {
+	if err := unmarshalOptions.Unmarshal(data, m); err != nil {
+		return status.Error(codes.Internal, "Failed to decode response: "+err.Error())
+	}
+	return nil
+}
+
+// decodeList читает ответ REST API со списком записей
+func decodeList[T any, P interface {
+	*T
+	proto.Message
+}](data []byte) ([]P, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to decode response: "+err.Error())
+	}
+
+	list := make([]P, 0, len(items))
+	for _, item := range items {
+		m := P(new(T))
+		if err := decode(item, m); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// decodeString читает ответ REST API, состоящий из одной строки, например статус
+func decodeString(data []byte) (string, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", status.Error(codes.Internal, "Failed to decode response: "+err.Error())
+	}
+	return s, nil
+}
+
+// pageQuery переводит параметры страницы в limit и offset; нулевые значения не передаются
+func pageQuery(page *tenderv1.Page) url.Values {
+	query := url.Values{}
+	if page.GetLimit() > 0 {
+		query.Set("limit", strconv.Itoa(int(page.GetLimit())))
+	}
+	if page.GetOffset() > 0 {
+		query.Set("offset", strconv.Itoa(int(page.GetOffset())))
+	}
+	return query
+}
+
+// responseBuffer сохраняет ответ маршрута в памяти
+type responseBuffer struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseBuffer) Header() http.Header {
+	return r.header
+}
+
+func (r *responseBuffer) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *responseBuffer) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(data)
+}
+
+// resourcePath собирает путь маршрута для ресурса с идентификатором id. Идентификатор проверяется заранее,
+// чтобы значение из запроса не могло изменить сам путь
+func resourcePath(prefix, field, id, action string) (string, error) {
+	if err := uuid.Validate(id); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "Invalid %s", field)
+	}
+	return prefix + id + action, nil
+}
@@ -0,0 +1,133 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"myapp/grpcapi/tenderv1"
+)
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		httpStatus int
+		want       codes.Code
+	}{
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusRequestEntityTooLarge, codes.InvalidArgument},
+		{http.StatusUnsupportedMediaType, codes.InvalidArgument},
+		{http.StatusUnauthorized, codes.Unauthenticated},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.AlreadyExists},
+		{http.StatusTooManyRequests, codes.ResourceExhausted},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{http.StatusInternalServerError, codes.Internal},
+		{http.StatusMethodNotAllowed, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if got := grpcCode(tt.httpStatus); got != tt.want {
+			t.Errorf("grpcCode(%d) = %s, want %s", tt.httpStatus, got, tt.want)
+		}
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name        string
+		httpStatus  int
+		body        string
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{"reason", http.StatusForbidden, `{"reason": "User is not authorized"}`, codes.PermissionDenied, "User is not authorized"},
+		{"error field", http.StatusTooManyRequests, `{"error": "Rate limit exceeded"}`, codes.ResourceExhausted, "Rate limit exceeded"},
+		{"empty body", http.StatusNotFound, ``, codes.NotFound, "Not Found"},
+		{"not json", http.StatusBadGateway, `<html>bad gateway</html>`, codes.Internal, "Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(statusError(tt.httpStatus, []byte(tt.body)))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMessage {
+				t.Errorf("statusError = %s %q, want %s %q", st.Code(), st.Message(), tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGatewayDo(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	gw := &gateway{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"reason": "Invitation already exists"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})}
+
+	ctx := context.WithValue(context.Background(), usernameKey{}, "user1")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", "req-1", "authorization", "secret"))
+
+	data, err := gw.do(ctx, request{method: http.MethodPost, path: "/api/tenders/new", body: map[string]string{"name": "Tender"}})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if string(data) != `{"ok": true}` {
+		t.Errorf("response = %s", data)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/api/tenders/new" {
+		t.Errorf("request = %s %s", got.Method, got.URL.Path)
+	}
+	if username := got.URL.Query().Get("username"); username != "user1" {
+		t.Errorf("username = %q, want user1", username)
+	}
+	if gotBody != `{"name":"Tender"}` || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("body = %s, content type = %q", gotBody, got.Header.Get("Content-Type"))
+	}
+	if got.Header.Get("X-Request-Id") != "req-1" {
+		t.Errorf("x-request-id is not forwarded")
+	}
+	if got.Header.Get("Authorization") != "" {
+		t.Errorf("metadata outside forwardedMetadata is forwarded")
+	}
+
+	_, err = gw.do(ctx, request{method: http.MethodGet, path: "/api/tenders", query: map[string][]string{"fail": {"1"}}})
+	if st := status.Convert(err); st.Code() != codes.AlreadyExists || st.Message() != "Invitation already exists" {
+		t.Errorf("error = %s %q, want AlreadyExists with reason", st.Code(), st.Message())
+	}
+}
+
+func TestCreateTenderIdentity(t *testing.T) {
+	var called bool
+	svc := &tenderService{gw: &gateway{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, _ = w.Write([]byte(`{}`))
+	})}}
+	ctx := context.WithValue(context.Background(), usernameKey{}, "user1")
+
+	_, err := svc.CreateTender(ctx, &tenderv1.CreateTenderRequest{CreatorUsername: "user2"})
+	if status.Code(err) != codes.PermissionDenied || called {
+		t.Errorf("error = %v, called = %v, want PermissionDenied without a REST call", err, called)
+	}
+
+	req := &tenderv1.CreateTenderRequest{}
+	if _, err := svc.CreateTender(ctx, req); err != nil {
+		t.Fatalf("CreateTender: %v", err)
+	}
+	if req.CreatorUsername != "user1" {
+		t.Errorf("creator = %q, want user1 from metadata", req.CreatorUsername)
+	}
+}
@@ -0,0 +1,120 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+
+	"myapp/grpcapi/tenderv1"
+)
+
+type reviewService struct {
+	tenderv1.UnimplementedReviewServiceServer
+	gw *gateway
+}
+
+func reviewPath(id, action string) (string, error) {
+	return resourcePath("/api/reviews/", "review_id", id, action)
+}
+
+func (s *reviewService) review(ctx context.Context, r request) (*tenderv1.Review, error) {
+	data, err := s.gw.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &tenderv1.Review{}
+	return review, decode(data, review)
+}
+
+func (s *reviewService) SubmitFeedback(ctx context.Context, req *tenderv1.SubmitFeedbackRequest) (*tenderv1.Bid, error) {
+	path, err := bidPath(req.BidId, "/feedback")
+	if err != nil {
+		return nil, err
+	}
+
+	review := req.Review
+	if review == nil {
+		review = &tenderv1.ReviewInput{}
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path, body: review})
+	if err != nil {
+		return nil, err
+	}
+
+	bid := &tenderv1.Bid{}
+	return bid, decode(data, bid)
+}
+
+// ListReviews запрашивает отзывы от имени пользователя из метаданных: маршрут определяет его по параметру requesterUsername
+func (s *reviewService) ListReviews(ctx context.Context, req *tenderv1.ListReviewsRequest) (*tenderv1.ListReviewsResponse, error) {
+	path, err := resourcePath("/api/bids/", "tender_id", req.TenderId, "/reviews")
+	if err != nil {
+		return nil, err
+	}
+
+	query := pageQuery(req.Page)
+	query.Set("requesterUsername", usernameFrom(ctx))
+	if req.AuthorUsername != "" {
+		query.Set("authorUsername", req.AuthorUsername)
+	}
+	if req.Scope != "" {
+		query.Set("scope", req.Scope)
+	}
+	if req.Order != "" {
+		query.Set("order", req.Order)
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path, query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := decodeList[tenderv1.Review](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListReviewsResponse{Reviews: reviews}, nil
+}
+
+func (s *reviewService) ListMyReviews(ctx context.Context, req *tenderv1.ListMyReviewsRequest) (*tenderv1.ListReviewsResponse, error) {
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: "/api/reviews/my", query: pageQuery(req.Page)})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := decodeList[tenderv1.Review](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListReviewsResponse{Reviews: reviews}, nil
+}
+
+func (s *reviewService) UpdateReview(ctx context.Context, req *tenderv1.UpdateReviewRequest) (*tenderv1.Review, error) {
+	path, err := reviewPath(req.ReviewId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := req.Changes
+	if changes == nil {
+		changes = &tenderv1.ReviewChanges{}
+	}
+	return s.review(ctx, request{method: http.MethodPatch, path: path, body: changes})
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, req *tenderv1.DeleteReviewRequest) (*tenderv1.Review, error) {
+	path, err := reviewPath(req.ReviewId, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.review(ctx, request{method: http.MethodDelete, path: path})
+}
+
+func (s *reviewService) ReplyToReview(ctx context.Context, req *tenderv1.ReplyToReviewRequest) (*tenderv1.Review, error) {
+	path, err := reviewPath(req.ReviewId, "/reply")
+	if err != nil {
+		return nil, err
+	}
+	return s.review(ctx, request{method: http.MethodPost, path: path, body: map[string]string{"text": req.Text}})
+}
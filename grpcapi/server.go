@@ -0,0 +1,98 @@
+// Package grpcapi - gRPC API тендеров, предложений, решений и отзывов. Каждый вызов выполняется
+// маршрутом REST API внутри процесса, поэтому проверки доступа, валидация, события, уведомления,
+// журнал и метрики у обоих API общие
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"myapp/grpcapi/tenderv1"
+	"myapp/health"
+)
+
+// UsernameMetadata - ключ метаданных с пользователем, от имени которого выполняется вызов.
+// Он заменяет параметр username REST API
+const UsernameMetadata = "username"
+
+// Сервисы API; проверка состояния отвечает по ним так же, как по пустому имени сервиса
+var services = []string{
+	tenderv1.TenderService_ServiceDesc.ServiceName,
+	tenderv1.BidService_ServiceDesc.ServiceName,
+	tenderv1.DecisionService_ServiceDesc.ServiceName,
+	tenderv1.ReviewService_ServiceDesc.ServiceName,
+}
+
+// NewServer создаёт gRPC-сервер, вызовы которого обслуживает handler - маршрутизатор REST API.
+// По db пользователь из метаданных сверяется с автором, которого REST API принимает в теле запроса.
+// Готовность в сервисе grpc.health.v1.Health определяет тот же checker, что и /readyz
+func NewServer(handler http.Handler, db *gorm.DB, checker *health.Checker, withReflection bool, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(authInterceptor))
+	srv := grpc.NewServer(opts...)
+
+	gw := &gateway{handler: handler}
+	tenderv1.RegisterTenderServiceServer(srv, &tenderService{gw: gw})
+	tenderv1.RegisterBidServiceServer(srv, &bidService{gw: gw, db: db})
+	tenderv1.RegisterDecisionServiceServer(srv, &decisionService{gw: gw})
+	tenderv1.RegisterReviewServiceServer(srv, &reviewService{gw: gw})
+	healthpb.RegisterHealthServer(srv, &healthService{checker: checker})
+
+	if withReflection {
+		reflection.Register(srv)
+	}
+
+	return srv
+}
+
+type usernameKey struct{}
+
+// authInterceptor требует пользователя в метаданных для всех сервисов API, кроме проверки состояния и reflection.
+// Существование пользователя и его права проверяют контроллеры, как и для REST API
+func authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	if !slices.Contains(services, service) {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(UsernameMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "Missing username metadata")
+	}
+
+	return handler(context.WithValue(ctx, usernameKey{}, values[0]), req)
+}
+
+func usernameFrom(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey{}).(string)
+	return username
+}
+
+// healthService отвечает на проверки состояния по результатам проверок готовности сервера.
+// Watch не поддерживается: клиенты в этом случае переходят на периодический вызов Check
+type healthService struct {
+	healthpb.UnimplementedHealthServer
+	checker *health.Checker
+}
+
+func (h *healthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.Service != "" && !slices.Contains(services, req.Service) {
+		return nil, status.Error(codes.NotFound, "Unknown service")
+	}
+
+	resp := &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}
+	if report := h.checker.Ready(ctx); report.Status != health.StatusOK {
+		resp.Status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"myapp/grpcapi/tenderv1"
+)
+
+type tenderService struct {
+	tenderv1.UnimplementedTenderServiceServer
+	gw *gateway
+}
+
+func tenderPath(id, action string) (string, error) {
+	return resourcePath("/api/tenders/", "tender_id", id, action)
+}
+
+func (s *tenderService) ListTenders(ctx context.Context, req *tenderv1.ListTendersRequest) (*tenderv1.ListTendersResponse, error) {
+	query := pageQuery(req.Page)
+	for _, serviceType := range req.ServiceTypes {
+		query.Add("service_type", serviceType)
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: "/api/tenders", query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	tenders, err := decodeList[tenderv1.Tender](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListTendersResponse{Tenders: tenders}, nil
+}
+
+func (s *tenderService) ListMyTenders(ctx context.Context, req *tenderv1.ListMyTendersRequest) (*tenderv1.ListTendersResponse, error) {
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: "/api/tenders/my", query: pageQuery(req.Page)})
+	if err != nil {
+		return nil, err
+	}
+
+	tenders, err := decodeList[tenderv1.Tender](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListTendersResponse{Tenders: tenders}, nil
+}
+
+func (s *tenderService) CreateTender(ctx context.Context, req *tenderv1.CreateTenderRequest) (*tenderv1.Tender, error) {
+	// Тендер создаётся от имени пользователя из метаданных, иначе любой вызов мог бы действовать за другого
+	username := usernameFrom(ctx)
+	if req.CreatorUsername == "" {
+		req.CreatorUsername = username
+	} else if req.CreatorUsername != username {
+		return nil, status.Error(codes.PermissionDenied, "Creator must be the calling user")
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPost, path: "/api/tenders/new", body: req})
+	if err != nil {
+		return nil, err
+	}
+
+	tender := &tenderv1.Tender{}
+	return tender, decode(data, tender)
+}
+
+func (s *tenderService) GetTenderStatus(ctx context.Context, req *tenderv1.GetTenderStatusRequest) (*tenderv1.TenderStatusResponse, error) {
+	path, err := tenderPath(req.TenderId, "/status")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := decodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.TenderStatusResponse{Status: status}, nil
+}
+
+func (s *tenderService) UpdateTenderStatus(ctx context.Context, req *tenderv1.UpdateTenderStatusRequest) (*tenderv1.Tender, error) {
+	path, err := tenderPath(req.TenderId, "/status")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path, query: url.Values{"status": {req.Status}}})
+	if err != nil {
+		return nil, err
+	}
+
+	tender := &tenderv1.Tender{}
+	return tender, decode(data, tender)
+}
+
+func (s *tenderService) EditTender(ctx context.Context, req *tenderv1.EditTenderRequest) (*tenderv1.Tender, error) {
+	path, err := tenderPath(req.TenderId, "/edit")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := req.Changes
+	if changes == nil {
+		changes = &tenderv1.TenderChanges{}
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPatch, path: path, body: changes})
+	if err != nil {
+		return nil, err
+	}
+
+	tender := &tenderv1.Tender{}
+	return tender, decode(data, tender)
+}
+
+func (s *tenderService) RollbackTender(ctx context.Context, req *tenderv1.RollbackTenderRequest) (*tenderv1.Tender, error) {
+	path, err := tenderPath(req.TenderId, "/rollback/"+strconv.Itoa(int(req.Version)))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	tender := &tenderv1.Tender{}
+	return tender, decode(data, tender)
+}
+
+func (s *tenderService) OpenTenderBids(ctx context.Context, req *tenderv1.OpenTenderBidsRequest) (*tenderv1.TenderOpening, error) {
+	path, err := tenderPath(req.TenderId, "/open")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPut, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	opening := &tenderv1.TenderOpening{}
+	return opening, decode(data, opening)
+}
+
+func (s *tenderService) CreateLot(ctx context.Context, req *tenderv1.CreateLotRequest) (*tenderv1.TenderLot, error) {
+	path, err := tenderPath(req.TenderId, "/lots")
+	if err != nil {
+		return nil, err
+	}
+
+	lot := req.Lot
+	if lot == nil {
+		lot = &tenderv1.LotInput{}
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodPost, path: path, body: lot})
+	if err != nil {
+		return nil, err
+	}
+
+	created := &tenderv1.TenderLot{}
+	return created, decode(data, created)
+}
+
+func (s *tenderService) ListLots(ctx context.Context, req *tenderv1.ListLotsRequest) (*tenderv1.ListLotsResponse, error) {
+	path, err := tenderPath(req.TenderId, "/lots")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.gw.do(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := decodeList[tenderv1.TenderLot](data)
+	if err != nil {
+		return nil, err
+	}
+	return &tenderv1.ListLotsResponse{Lots: lots}, nil
+}
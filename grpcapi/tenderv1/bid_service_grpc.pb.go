@@ -0,0 +1,505 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: bid_service.proto
+
+package tenderv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BidService_CreateBid_FullMethodName       = "/tender.v1.BidService/CreateBid"
+	BidService_ListMyBids_FullMethodName      = "/tender.v1.BidService/ListMyBids"
+	BidService_ListTenderBids_FullMethodName  = "/tender.v1.BidService/ListTenderBids"
+	BidService_GetBidStatus_FullMethodName    = "/tender.v1.BidService/GetBidStatus"
+	BidService_UpdateBidStatus_FullMethodName = "/tender.v1.BidService/UpdateBidStatus"
+	BidService_EditBid_FullMethodName         = "/tender.v1.BidService/EditBid"
+	BidService_RollbackBid_FullMethodName     = "/tender.v1.BidService/RollbackBid"
+	BidService_LowerBidPrice_FullMethodName   = "/tender.v1.BidService/LowerBidPrice"
+	BidService_WithdrawBid_FullMethodName     = "/tender.v1.BidService/WithdrawBid"
+	BidService_ResubmitBid_FullMethodName     = "/tender.v1.BidService/ResubmitBid"
+	BidService_ListWithdrawals_FullMethodName = "/tender.v1.BidService/ListWithdrawals"
+)
+
+// BidServiceClient is the client API for BidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BidService повторяет маршруты /api/bids, кроме вложений
+type BidServiceClient interface {
+	CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	ListMyBids(ctx context.Context, in *ListMyBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error)
+	ListTenderBids(ctx context.Context, in *ListTenderBidsRequest, opts ...grpc.CallOption) (*ListTenderBidsResponse, error)
+	GetBidStatus(ctx context.Context, in *GetBidStatusRequest, opts ...grpc.CallOption) (*BidStatusResponse, error)
+	UpdateBidStatus(ctx context.Context, in *UpdateBidStatusRequest, opts ...grpc.CallOption) (*Bid, error)
+	EditBid(ctx context.Context, in *EditBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	RollbackBid(ctx context.Context, in *RollbackBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	LowerBidPrice(ctx context.Context, in *LowerBidPriceRequest, opts ...grpc.CallOption) (*Bid, error)
+	WithdrawBid(ctx context.Context, in *WithdrawBidRequest, opts ...grpc.CallOption) (*BidWithdrawal, error)
+	ResubmitBid(ctx context.Context, in *ResubmitBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	ListWithdrawals(ctx context.Context, in *ListWithdrawalsRequest, opts ...grpc.CallOption) (*ListWithdrawalsResponse, error)
+}
+
+type bidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBidServiceClient(cc grpc.ClientConnInterface) BidServiceClient {
+	return &bidServiceClient{cc}
+}
+
+func (c *bidServiceClient) CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_CreateBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListMyBids(ctx context.Context, in *ListMyBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBidsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListMyBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListTenderBids(ctx context.Context, in *ListTenderBidsRequest, opts ...grpc.CallOption) (*ListTenderBidsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenderBidsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListTenderBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) GetBidStatus(ctx context.Context, in *GetBidStatusRequest, opts ...grpc.CallOption) (*BidStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BidStatusResponse)
+	err := c.cc.Invoke(ctx, BidService_GetBidStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) UpdateBidStatus(ctx context.Context, in *UpdateBidStatusRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_UpdateBidStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) EditBid(ctx context.Context, in *EditBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_EditBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) RollbackBid(ctx context.Context, in *RollbackBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_RollbackBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) LowerBidPrice(ctx context.Context, in *LowerBidPriceRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_LowerBidPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) WithdrawBid(ctx context.Context, in *WithdrawBidRequest, opts ...grpc.CallOption) (*BidWithdrawal, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BidWithdrawal)
+	err := c.cc.Invoke(ctx, BidService_WithdrawBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ResubmitBid(ctx context.Context, in *ResubmitBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_ResubmitBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListWithdrawals(ctx context.Context, in *ListWithdrawalsRequest, opts ...grpc.CallOption) (*ListWithdrawalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWithdrawalsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListWithdrawals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BidServiceServer is the server API for BidService service.
+// All implementations must embed UnimplementedBidServiceServer
+// for forward compatibility.
+//
+// BidService повторяет маршруты /api/bids, кроме вложений
+type BidServiceServer interface {
+	CreateBid(context.Context, *CreateBidRequest) (*Bid, error)
+	ListMyBids(context.Context, *ListMyBidsRequest) (*ListBidsResponse, error)
+	ListTenderBids(context.Context, *ListTenderBidsRequest) (*ListTenderBidsResponse, error)
+	GetBidStatus(context.Context, *GetBidStatusRequest) (*BidStatusResponse, error)
+	UpdateBidStatus(context.Context, *UpdateBidStatusRequest) (*Bid, error)
+	EditBid(context.Context, *EditBidRequest) (*Bid, error)
+	RollbackBid(context.Context, *RollbackBidRequest) (*Bid, error)
+	LowerBidPrice(context.Context, *LowerBidPriceRequest) (*Bid, error)
+	WithdrawBid(context.Context, *WithdrawBidRequest) (*BidWithdrawal, error)
+	ResubmitBid(context.Context, *ResubmitBidRequest) (*Bid, error)
+	ListWithdrawals(context.Context, *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error)
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+// UnimplementedBidServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBidServiceServer struct{}
+
+func (UnimplementedBidServiceServer) CreateBid(context.Context, *CreateBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListMyBids(context.Context, *ListMyBidsRequest) (*ListBidsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyBids not implemented")
+}
+func (UnimplementedBidServiceServer) ListTenderBids(context.Context, *ListTenderBidsRequest) (*ListTenderBidsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenderBids not implemented")
+}
+func (UnimplementedBidServiceServer) GetBidStatus(context.Context, *GetBidStatusRequest) (*BidStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBidStatus not implemented")
+}
+func (UnimplementedBidServiceServer) UpdateBidStatus(context.Context, *UpdateBidStatusRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBidStatus not implemented")
+}
+func (UnimplementedBidServiceServer) EditBid(context.Context, *EditBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditBid not implemented")
+}
+func (UnimplementedBidServiceServer) RollbackBid(context.Context, *RollbackBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackBid not implemented")
+}
+func (UnimplementedBidServiceServer) LowerBidPrice(context.Context, *LowerBidPriceRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LowerBidPrice not implemented")
+}
+func (UnimplementedBidServiceServer) WithdrawBid(context.Context, *WithdrawBidRequest) (*BidWithdrawal, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithdrawBid not implemented")
+}
+func (UnimplementedBidServiceServer) ResubmitBid(context.Context, *ResubmitBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResubmitBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListWithdrawals(context.Context, *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWithdrawals not implemented")
+}
+func (UnimplementedBidServiceServer) mustEmbedUnimplementedBidServiceServer() {}
+func (UnimplementedBidServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeBidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BidServiceServer will
+// result in compilation errors.
+type UnsafeBidServiceServer interface {
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+func RegisterBidServiceServer(s grpc.ServiceRegistrar, srv BidServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBidServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BidService_ServiceDesc, srv)
+}
+
+func _BidService_CreateBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).CreateBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_CreateBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).CreateBid(ctx, req.(*CreateBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListMyBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListMyBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListMyBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListMyBids(ctx, req.(*ListMyBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListTenderBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenderBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListTenderBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListTenderBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListTenderBids(ctx, req.(*ListTenderBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_GetBidStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBidStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).GetBidStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_GetBidStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).GetBidStatus(ctx, req.(*GetBidStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_UpdateBidStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBidStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).UpdateBidStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_UpdateBidStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).UpdateBidStatus(ctx, req.(*UpdateBidStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_EditBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).EditBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_EditBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).EditBid(ctx, req.(*EditBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_RollbackBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).RollbackBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_RollbackBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).RollbackBid(ctx, req.(*RollbackBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_LowerBidPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LowerBidPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).LowerBidPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_LowerBidPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).LowerBidPrice(ctx, req.(*LowerBidPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_WithdrawBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).WithdrawBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_WithdrawBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).WithdrawBid(ctx, req.(*WithdrawBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ResubmitBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResubmitBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ResubmitBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ResubmitBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ResubmitBid(ctx, req.(*ResubmitBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListWithdrawals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWithdrawalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListWithdrawals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListWithdrawals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListWithdrawals(ctx, req.(*ListWithdrawalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BidService_ServiceDesc is the grpc.ServiceDesc for BidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tender.v1.BidService",
+	HandlerType: (*BidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBid",
+			Handler:    _BidService_CreateBid_Handler,
+		},
+		{
+			MethodName: "ListMyBids",
+			Handler:    _BidService_ListMyBids_Handler,
+		},
+		{
+			MethodName: "ListTenderBids",
+			Handler:    _BidService_ListTenderBids_Handler,
+		},
+		{
+			MethodName: "GetBidStatus",
+			Handler:    _BidService_GetBidStatus_Handler,
+		},
+		{
+			MethodName: "UpdateBidStatus",
+			Handler:    _BidService_UpdateBidStatus_Handler,
+		},
+		{
+			MethodName: "EditBid",
+			Handler:    _BidService_EditBid_Handler,
+		},
+		{
+			MethodName: "RollbackBid",
+			Handler:    _BidService_RollbackBid_Handler,
+		},
+		{
+			MethodName: "LowerBidPrice",
+			Handler:    _BidService_LowerBidPrice_Handler,
+		},
+		{
+			MethodName: "WithdrawBid",
+			Handler:    _BidService_WithdrawBid_Handler,
+		},
+		{
+			MethodName: "ResubmitBid",
+			Handler:    _BidService_ResubmitBid_Handler,
+		},
+		{
+			MethodName: "ListWithdrawals",
+			Handler:    _BidService_ListWithdrawals_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bid_service.proto",
+}
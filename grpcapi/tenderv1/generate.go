@@ -0,0 +1,4 @@
+// Package tenderv1 содержит сообщения и сервисы gRPC API, сгенерированные из файлов *.proto
+package tenderv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative types.proto tender_service.proto bid_service.proto decision_service.proto review_service.proto
@@ -0,0 +1,467 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tender_service.proto
+
+package tenderv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TenderService_ListTenders_FullMethodName        = "/tender.v1.TenderService/ListTenders"
+	TenderService_ListMyTenders_FullMethodName      = "/tender.v1.TenderService/ListMyTenders"
+	TenderService_CreateTender_FullMethodName       = "/tender.v1.TenderService/CreateTender"
+	TenderService_GetTenderStatus_FullMethodName    = "/tender.v1.TenderService/GetTenderStatus"
+	TenderService_UpdateTenderStatus_FullMethodName = "/tender.v1.TenderService/UpdateTenderStatus"
+	TenderService_EditTender_FullMethodName         = "/tender.v1.TenderService/EditTender"
+	TenderService_RollbackTender_FullMethodName     = "/tender.v1.TenderService/RollbackTender"
+	TenderService_OpenTenderBids_FullMethodName     = "/tender.v1.TenderService/OpenTenderBids"
+	TenderService_CreateLot_FullMethodName          = "/tender.v1.TenderService/CreateLot"
+	TenderService_ListLots_FullMethodName           = "/tender.v1.TenderService/ListLots"
+)
+
+// TenderServiceClient is the client API for TenderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TenderService повторяет маршруты /api/tenders. Пользователь передаётся в метаданных username
+type TenderServiceClient interface {
+	ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error)
+	ListMyTenders(ctx context.Context, in *ListMyTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error)
+	CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	GetTenderStatus(ctx context.Context, in *GetTenderStatusRequest, opts ...grpc.CallOption) (*TenderStatusResponse, error)
+	UpdateTenderStatus(ctx context.Context, in *UpdateTenderStatusRequest, opts ...grpc.CallOption) (*Tender, error)
+	EditTender(ctx context.Context, in *EditTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	RollbackTender(ctx context.Context, in *RollbackTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	OpenTenderBids(ctx context.Context, in *OpenTenderBidsRequest, opts ...grpc.CallOption) (*TenderOpening, error)
+	CreateLot(ctx context.Context, in *CreateLotRequest, opts ...grpc.CallOption) (*TenderLot, error)
+	ListLots(ctx context.Context, in *ListLotsRequest, opts ...grpc.CallOption) (*ListLotsResponse, error)
+}
+
+type tenderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenderServiceClient(cc grpc.ClientConnInterface) TenderServiceClient {
+	return &tenderServiceClient{cc}
+}
+
+func (c *tenderServiceClient) ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTendersResponse)
+	err := c.cc.Invoke(ctx, TenderService_ListTenders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) ListMyTenders(ctx context.Context, in *ListMyTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTendersResponse)
+	err := c.cc.Invoke(ctx, TenderService_ListMyTenders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_CreateTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) GetTenderStatus(ctx context.Context, in *GetTenderStatusRequest, opts ...grpc.CallOption) (*TenderStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TenderStatusResponse)
+	err := c.cc.Invoke(ctx, TenderService_GetTenderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) UpdateTenderStatus(ctx context.Context, in *UpdateTenderStatusRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_UpdateTenderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) EditTender(ctx context.Context, in *EditTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_EditTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) RollbackTender(ctx context.Context, in *RollbackTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_RollbackTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) OpenTenderBids(ctx context.Context, in *OpenTenderBidsRequest, opts ...grpc.CallOption) (*TenderOpening, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TenderOpening)
+	err := c.cc.Invoke(ctx, TenderService_OpenTenderBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) CreateLot(ctx context.Context, in *CreateLotRequest, opts ...grpc.CallOption) (*TenderLot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TenderLot)
+	err := c.cc.Invoke(ctx, TenderService_CreateLot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) ListLots(ctx context.Context, in *ListLotsRequest, opts ...grpc.CallOption) (*ListLotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLotsResponse)
+	err := c.cc.Invoke(ctx, TenderService_ListLots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenderServiceServer is the server API for TenderService service.
+// All implementations must embed UnimplementedTenderServiceServer
+// for forward compatibility.
+//
+// TenderService повторяет маршруты /api/tenders. Пользователь передаётся в метаданных username
+type TenderServiceServer interface {
+	ListTenders(context.Context, *ListTendersRequest) (*ListTendersResponse, error)
+	ListMyTenders(context.Context, *ListMyTendersRequest) (*ListTendersResponse, error)
+	CreateTender(context.Context, *CreateTenderRequest) (*Tender, error)
+	GetTenderStatus(context.Context, *GetTenderStatusRequest) (*TenderStatusResponse, error)
+	UpdateTenderStatus(context.Context, *UpdateTenderStatusRequest) (*Tender, error)
+	EditTender(context.Context, *EditTenderRequest) (*Tender, error)
+	RollbackTender(context.Context, *RollbackTenderRequest) (*Tender, error)
+	OpenTenderBids(context.Context, *OpenTenderBidsRequest) (*TenderOpening, error)
+	CreateLot(context.Context, *CreateLotRequest) (*TenderLot, error)
+	ListLots(context.Context, *ListLotsRequest) (*ListLotsResponse, error)
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+// UnimplementedTenderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTenderServiceServer struct{}
+
+func (UnimplementedTenderServiceServer) ListTenders(context.Context, *ListTendersRequest) (*ListTendersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenders not implemented")
+}
+func (UnimplementedTenderServiceServer) ListMyTenders(context.Context, *ListMyTendersRequest) (*ListTendersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyTenders not implemented")
+}
+func (UnimplementedTenderServiceServer) CreateTender(context.Context, *CreateTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTender not implemented")
+}
+func (UnimplementedTenderServiceServer) GetTenderStatus(context.Context, *GetTenderStatusRequest) (*TenderStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenderStatus not implemented")
+}
+func (UnimplementedTenderServiceServer) UpdateTenderStatus(context.Context, *UpdateTenderStatusRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenderStatus not implemented")
+}
+func (UnimplementedTenderServiceServer) EditTender(context.Context, *EditTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditTender not implemented")
+}
+func (UnimplementedTenderServiceServer) RollbackTender(context.Context, *RollbackTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackTender not implemented")
+}
+func (UnimplementedTenderServiceServer) OpenTenderBids(context.Context, *OpenTenderBidsRequest) (*TenderOpening, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenTenderBids not implemented")
+}
+func (UnimplementedTenderServiceServer) CreateLot(context.Context, *CreateLotRequest) (*TenderLot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLot not implemented")
+}
+func (UnimplementedTenderServiceServer) ListLots(context.Context, *ListLotsRequest) (*ListLotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLots not implemented")
+}
+func (UnimplementedTenderServiceServer) mustEmbedUnimplementedTenderServiceServer() {}
+func (UnimplementedTenderServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeTenderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenderServiceServer will
+// result in compilation errors.
+type UnsafeTenderServiceServer interface {
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+func RegisterTenderServiceServer(s grpc.ServiceRegistrar, srv TenderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTenderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TenderService_ServiceDesc, srv)
+}
+
+func _TenderService_ListTenders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTendersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).ListTenders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_ListTenders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).ListTenders(ctx, req.(*ListTendersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_ListMyTenders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyTendersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).ListMyTenders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_ListMyTenders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).ListMyTenders(ctx, req.(*ListMyTendersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_CreateTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).CreateTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_CreateTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).CreateTender(ctx, req.(*CreateTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_GetTenderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).GetTenderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_GetTenderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).GetTenderStatus(ctx, req.(*GetTenderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_UpdateTenderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).UpdateTenderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_UpdateTenderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).UpdateTenderStatus(ctx, req.(*UpdateTenderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_EditTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).EditTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_EditTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).EditTender(ctx, req.(*EditTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_RollbackTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).RollbackTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_RollbackTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).RollbackTender(ctx, req.(*RollbackTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_OpenTenderBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenTenderBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).OpenTenderBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_OpenTenderBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).OpenTenderBids(ctx, req.(*OpenTenderBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_CreateLot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).CreateLot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_CreateLot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).CreateLot(ctx, req.(*CreateLotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_ListLots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).ListLots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_ListLots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).ListLots(ctx, req.(*ListLotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TenderService_ServiceDesc is the grpc.ServiceDesc for TenderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TenderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tender.v1.TenderService",
+	HandlerType: (*TenderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTenders",
+			Handler:    _TenderService_ListTenders_Handler,
+		},
+		{
+			MethodName: "ListMyTenders",
+			Handler:    _TenderService_ListMyTenders_Handler,
+		},
+		{
+			MethodName: "CreateTender",
+			Handler:    _TenderService_CreateTender_Handler,
+		},
+		{
+			MethodName: "GetTenderStatus",
+			Handler:    _TenderService_GetTenderStatus_Handler,
+		},
+		{
+			MethodName: "UpdateTenderStatus",
+			Handler:    _TenderService_UpdateTenderStatus_Handler,
+		},
+		{
+			MethodName: "EditTender",
+			Handler:    _TenderService_EditTender_Handler,
+		},
+		{
+			MethodName: "RollbackTender",
+			Handler:    _TenderService_RollbackTender_Handler,
+		},
+		{
+			MethodName: "OpenTenderBids",
+			Handler:    _TenderService_OpenTenderBids_Handler,
+		},
+		{
+			MethodName: "CreateLot",
+			Handler:    _TenderService_CreateLot_Handler,
+		},
+		{
+			MethodName: "ListLots",
+			Handler:    _TenderService_ListLots_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tender_service.proto",
+}
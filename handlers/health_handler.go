@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"myapp/health"
+)
+
+// LivenessHandler подтверждает, что процесс жив и обрабатывает запросы. Зависимости не проверяются,
+// чтобы недоступность базы данных не приводила к перезапуску сервиса
+func LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// ReadinessHandler проверяет зависимости сервиса и отвечает 503, если хотя бы одна недоступна
+// или сервер останавливается
+func ReadinessHandler(checker *health.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != health.StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, report)
+	}
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check проверяет одну зависимость сервиса и возвращает ошибку, если она недоступна
+type Check func(ctx context.Context) error
+
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker собирает проверки зависимостей для готовности сервиса принимать запросы
+type Checker struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+func (h *Checker) Add(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// Drain переводит сервис в состояние остановки: проверка готовности начинает отвечать отказом,
+// чтобы балансировщик перестал направлять новые запросы до закрытия сервера
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Ready выполняет все проверки параллельно с общим таймаутом
+func (h *Checker) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentStatus, len(h.checks)+1)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range h.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := run(ctx, nc.check)
+			status := ComponentStatus{Status: StatusOK, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				status.Status = StatusFail
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Components[nc.name] = status
+			if err != nil {
+				report.Status = StatusFail
+			}
+			mu.Unlock()
+		}(nc)
+	}
+	wg.Wait()
+
+	if h.draining.Load() {
+		report.Status = StatusFail
+		report.Components["shutdown"] = ComponentStatus{Status: StatusFail, Error: "server is shutting down"}
+	}
+
+	return report
+}
+
+// run не даёт зависшей проверке задержать ответ дольше таймаута
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New("check timed out")
+	}
+}
+
+// Heartbeat отмечает время последней работы фонового обработчика
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+func (hb *Heartbeat) Beat() {
+	hb.last.Store(time.Now().UnixNano())
+}
+
+// Since возвращает время с последней отметки и false, если отметок ещё не было
+func (hb *Heartbeat) Since() (time.Duration, bool) {
+	last := hb.last.Load()
+	if last == 0 {
+		return 0, false
+	}
+	return time.Since(time.Unix(0, last)), true
+}
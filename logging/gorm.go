@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger передаёт сообщения GORM в структурированный журнал. Медленные запросы пишутся с уровнем Warn,
+// остальные запросы и их ошибки - с уровнем Debug: ошибку, повлиявшую на ответ, записывает обработчик запроса
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+func (l GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	slog.InfoContext(ctx, msg, "args", args)
+}
+
+func (l GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	slog.WarnContext(ctx, msg, "args", args)
+}
+
+func (l GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	slog.ErrorContext(ctx, msg, "args", args)
+}
+
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	slow := l.SlowThreshold > 0 && elapsed > l.SlowThreshold
+
+	level := slog.LevelDebug
+	if slow {
+		level = slog.LevelWarn
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Duration("elapsed", elapsed),
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	msg := "SQL query"
+	if slow {
+		msg = "Slow SQL query"
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
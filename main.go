@@ -1,217 +1,118 @@
 package main
 
 import (
-	"context"
 	"errors"
-	"log"
-	"myapp/config"
-	"myapp/models"
-	"myapp/router"
-	"net/http"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"strings"
 
-	"github.com/gin-gonic/gin"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"myapp/config"
+	"myapp/database"
+	"myapp/logging"
 )
 
-var db *gorm.DB
-
-func init() {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatal("Failed to load config: ", err)
-	}
-
-	db, err = gorm.Open(postgres.Open(cfg.PostgresConn), &gorm.Config{})
-	if err != nil {
-		log.Fatal("Failed to connect to the database: ", err)
-	}
-
-	log.Println("Successfully connected to the database")
+// command - подкоманда исполняемого файла. Все команды используют общие настройки и подключение к базе данных
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config.Config, args []string) error
+}
 
-	// Установка расширения uuid-ossp
-	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`).Error; err != nil {
-		log.Fatal("Failed to create extension uuid-ossp: ", err)
-	}
+var commands = []command{
+	{"serve", "apply migrations and start the HTTP server (default)", runServe},
+	{"migrate", "apply database migrations and exit", runMigrate},
+	{"seed", "load fixtures from -file (repeatable) or the demo dataset", runSeed},
+	{"config print", "print effective configuration with secrets redacted", runConfigPrint},
+	{"openapi print", "print the OpenAPI specification as JSON", runOpenAPIPrint},
+	{"openapi check", "verify that every HTTP route is documented in the OpenAPI specification", runOpenAPICheck},
+	{"user create", "create an employee", runUserCreate},
+	{"org create", "create an organization", runOrgCreate},
+	{"org add-responsible", "make an employee responsible for an organization", runOrgAddResponsible},
+	{"tender close", "close a published tender", runTenderClose},
+	{"tender export", "export a tender with bids, decisions and reviews as JSON", runTenderExport},
+}
 
-	// Создание типа organization_type
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE organization_type AS ENUM ('IE', 'LLC', 'JSC');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type organization_type: ", err)
-	}
+// uncheckedCommands - команды, которым настройки передаются до проверки: config print выводит их,
+// чтобы по выводу можно было найти ошибку, и только потом сообщает о ней
+var uncheckedCommands = map[string]bool{"config print": true}
 
-	// Создание типа service_type
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE service_type AS ENUM ('Construction', 'Delivery', 'Manufacture');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type service_type: ", err)
-	}
+func main() {
+	flag.Usage = usage
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to YAML config file")
+	flag.Parse()
 
-	// Создание типа status
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE status AS ENUM ('Created', 'Published', 'Closed');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type status: ", err)
+	// Без команды запускается сервер, как и раньше
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"serve"}
 	}
 
-	// Создание типа bid_status
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE bid_status AS ENUM ('Created', 'Published', 'Canceled');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type bid_status: ", err)
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", strings.Join(args, " "))
+		usage()
+		os.Exit(2)
 	}
 
-	// Создание типа author_type
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE author_type AS ENUM ('Organization', 'User');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type author_type: ", err)
+	// Команды из uncheckedCommands получают настройки без проверки и проверяют их сами
+	unchecked := uncheckedCommands[cmd.name]
+	load := config.LoadConfig
+	if unchecked {
+		load = config.ReadConfig
 	}
 
-	// Создание типа decision_type
-	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE decision_type AS ENUM ('Approved', 'Rejected');
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
-		log.Fatal("Failed to create type decision_type: ", err)
+	cfg, err := load(*configPath)
+	if err != nil {
+		fatal("Failed to load config", err)
 	}
 
-	// Автоматическая миграция таблиц
-	err = db.AutoMigrate(&models.Employee{}, &models.Organization{}, &models.OrganizationResponsible{}, &models.Tender{}, &models.TenderHistory{}, &models.Bid{}, &models.BidHistory{}, &models.Decision{}, &models.Review{})
-
-	// Создание функции для триггера обновления истории тендера
-	if err := db.Exec(`
-        CREATE OR REPLACE FUNCTION update_tender_history() RETURNS TRIGGER AS $$
-        BEGIN
-            -- Вставка записи в таблицу истории до изменений
-            INSERT INTO tender_histories (id, tender_id, name, description, service_type, status, version, created_at)
-            VALUES (uuid_generate_v4(), OLD.id, OLD.name, OLD.description, OLD.service_type, OLD.status, OLD.version, NOW());
-
-            -- Увеличение версии тендера
-            NEW.version := OLD.version + 1;
-
-            RETURN NEW;
-        END;
-        $$ LANGUAGE plpgsql;
-    `).Error; err != nil {
-		log.Fatal("Failed to create function update_tender_history: ", err)
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil && !unchecked {
+		fatal("Failed to configure logging", err)
 	}
 
-	// Создание триггера обновления истории тендера
-	if err := db.Exec(`
-    DO $$
-    BEGIN
-        IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'tender_update_trigger') THEN
-            CREATE TRIGGER tender_update_trigger
-            BEFORE UPDATE ON tenders
-            FOR EACH ROW
-            EXECUTE FUNCTION update_tender_history();
-        END IF;
-    END $$;
-`).Error; err != nil {
-		log.Fatal("Failed to create trigger tender_update_trigger: ", err)
+	if err := cmd.run(cfg, rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fatal("Command "+cmd.name+" failed", err)
 	}
+}
 
-	// Создание функции для триггера обновления истории предложений
-	if err := db.Exec(`
-    CREATE OR REPLACE FUNCTION update_bid_history() RETURNS TRIGGER AS $$
-    BEGIN
-        -- Вставка записи в таблицу истории до изменений
-        INSERT INTO bid_histories (id, bid_id, name, description, status, version, created_at)
-        VALUES (uuid_generate_v4(), OLD.id, OLD.name, OLD.description, OLD.status, OLD.version, NOW());
-
-        -- Увеличение версии предложения
-        NEW.version := OLD.version + 1;
-
-        RETURN NEW;
-    END;
-    $$ LANGUAGE plpgsql;
-`).Error; err != nil {
-		log.Fatal("Failed to create function update_bid_history: ", err)
+// findCommand выбирает команду по первым словам аргументов и возвращает оставшиеся аргументы
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
 	}
+	return command{}, nil, false
+}
 
-	// Создание триггера обновления истории предложений
-	if err := db.Exec(`
-DO $$
-BEGIN
-    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'bid_update_trigger') THEN
-        CREATE TRIGGER bid_update_trigger
-        BEFORE UPDATE ON bids
-        FOR EACH ROW
-        EXECUTE FUNCTION update_bid_history();
-    END IF;
-END $$;
-`).Error; err != nil {
-		log.Fatal("Failed to create trigger bid_update_trigger: ", err)
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [-config file] <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", cmd.name, cmd.usage)
 	}
-
+	fmt.Fprintln(os.Stderr, "\nRun '<command> -h' for command flags.")
 }
 
-func main() {
-	cfg, err := config.LoadConfig()
+// connect подключается к базе данных с настройками пула
+func connect(cfg *config.Config) (*gorm.DB, error) {
+	db, err := database.Open(cfg.DB)
 	if err != nil {
-		log.Fatal("Failed to load config: ", err)
-	}
-
-	if cfg.GinMode != "" {
-		gin.SetMode(cfg.GinMode)
-	}
-
-	r := router.SetupRouter(db)
-
-	srv := &http.Server{
-		Addr:    cfg.ServerAddress,
-		Handler: r,
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
 	}
+	slog.Info("Successfully connected to the database")
 
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("listen: %s\n", err)
-		}
-	}()
-
-	log.Printf("Starting server at %s in %s mode\n", cfg.ServerAddress, gin.Mode())
-
-	// Ожидание сигнала для остановки сервера
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// Контекст с таймаутом для завершения текущих запросов
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
-	}
+	return db, nil
+}
 
-	log.Println("Server exiting")
+// fatal записывает ошибку в журнал и завершает процесс
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const namespace = "tender"
+
+// Registry - собственный реестр метрик, чтобы не публиковать метрики сторонних библиотек из глобального реестра
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	TendersCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tenders_created_total",
+		Help:      "Tenders created.",
+	})
+
+	TenderStatusChanges = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tender_status_changes_total",
+		Help:      "Tender status transitions by new status.",
+	}, []string{"status"})
+
+	Decisions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "decisions_total",
+		Help:      "Decisions submitted by type.",
+	}, []string{"type"})
+
+	QuorumClosures = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quorum_closures_total",
+		Help:      "Tenders closed after reaching the approval quorum.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// Register подключает метрики базы данных: длительность и ошибки запросов GORM, состояние пула соединений
+// и количество тендеров и предложений по статусам
+func Register(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	if err := db.Use(gormPlugin{}); err != nil {
+		return err
+	}
+
+	Registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, "postgres"), newStatusCollector(db))
+	return nil
+}
+
+// Middleware учитывает запросы по шаблону маршрута, а не по фактическому пути,
+// чтобы идентификаторы в URL не порождали новых рядов
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler отдаёт метрики. Если задан токен, запрос должен передавать его в заголовке Authorization: Bearer
+func Handler(token string) http.Handler {
+	metricsHandler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return metricsHandler
+	}
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		metricsHandler.ServeHTTP(w, r)
+	})
+}
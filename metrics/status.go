@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+var (
+	tendersDesc = prometheus.NewDesc(namespace+"_tenders", "Tenders by status.", []string{"status"}, nil)
+	bidsDesc    = prometheus.NewDesc(namespace+"_bids", "Bids by status.", []string{"status"}, nil)
+)
+
+// statusCollector считает тендеры и предложения по статусам в момент сбора метрик
+type statusCollector struct {
+	db *gorm.DB
+}
+
+func newStatusCollector(db *gorm.DB) prometheus.Collector {
+	return statusCollector{db: db}
+}
+
+func (c statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tendersDesc
+	ch <- bidsDesc
+}
+
+func (c statusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch, "tenders", tendersDesc)
+	c.collect(ch, "bids", bidsDesc)
+}
+
+func (c statusCollector) collect(ch chan<- prometheus.Metric, table string, desc *prometheus.Desc) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+
+	if err := c.db.Table(table).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		slog.Error("Failed to collect status metrics", "table", table, "error", err)
+		return
+	}
+
+	for _, row := range rows {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(row.Count), row.Status)
+	}
+}
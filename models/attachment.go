@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Attachment struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID    *uuid.UUID `gorm:"type:uuid;index" json:"tenderId,omitempty"`
+	BidID       *uuid.UUID `gorm:"type:uuid;index" json:"bidId,omitempty"`
+	Version     int        `gorm:"type:int;not null" json:"version"`
+	FileName    string     `gorm:"type:varchar(255);not null" json:"fileName"`
+	ContentType string     `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size        int64      `gorm:"not null" json:"size"`
+	Checksum    string     `gorm:"type:varchar(64);not null" json:"checksum"`
+	StorageKey  string     `gorm:"type:varchar(255);not null" json:"-"`
+	UploadedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"uploadedBy"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
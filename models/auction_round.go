@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoundStatus string
+
+const (
+	RoundOpen   RoundStatus = "Open"
+	RoundClosed RoundStatus = "Closed"
+)
+
+// AuctionRound - раунд понижения цены в тендере с обратным аукционом
+type AuctionRound struct {
+	ID        uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID  uuid.UUID   `gorm:"type:uuid;not null;index" json:"tenderId"`
+	Number    int         `gorm:"type:int;not null" json:"number"`
+	Status    RoundStatus `gorm:"type:varchar(20);not null" json:"status"`
+	BestPrice *float64    `gorm:"type:numeric(15,2)" json:"bestPrice,omitempty"`
+	EndsAt    time.Time   `gorm:"not null" json:"endsAt"`
+	ClosedAt  *time.Time  `json:"closedAt,omitempty"`
+	CreatedAt time.Time   `gorm:"autoCreateTime" json:"createdAt"`
+	Tender    Tender      `gorm:"foreignKey:TenderID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
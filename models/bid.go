@@ -18,16 +18,21 @@ const (
 	BidCreated   BidStatus = "Created"
 	BidPublished BidStatus = "Published"
 	BidCanceled  BidStatus = "Canceled"
+	BidWithdrawn BidStatus = "Withdrawn"
 )
 
 type Bid struct {
-	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
-	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
-	Description string     `gorm:"type:varchar(500);not null" json:"description"`
-	Status      BidStatus  `gorm:"type:bid_status;not null" json:"status"`
-	TenderID    uuid.UUID  `gorm:"type:uuid;not null" json:"tenderId"`
-	AuthorType  AuthorType `gorm:"type:author_type;not null" json:"authorType"`
-	AuthorID    uuid.UUID  `gorm:"type:uuid;not null" json:"authorId"`
-	Version     int        `gorm:"type:int;default:1" json:"version"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	ID             uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	Name           string      `gorm:"type:varchar(100);not null" json:"name"`
+	Description    string      `gorm:"type:varchar(500);not null" json:"description"`
+	Status         BidStatus   `gorm:"type:bid_status;not null" json:"status"`
+	TenderID       uuid.UUID   `gorm:"type:uuid;not null" json:"tenderId"`
+	AuthorType     AuthorType  `gorm:"type:author_type;not null" json:"authorType"`
+	AuthorID       uuid.UUID   `gorm:"type:uuid;not null" json:"authorId"`
+	OrganizationID *uuid.UUID  `gorm:"type:uuid;index" json:"organizationId,omitempty"`
+	Price          *float64    `gorm:"type:numeric(15,2)" json:"price,omitempty"`
+	Version        int         `gorm:"type:int;default:1" json:"version"`
+	CreatedAt      time.Time   `gorm:"autoCreateTime" json:"createdAt"`
+	Lots           []BidLot    `gorm:"foreignKey:BidID;references:ID;constraint:OnDelete:CASCADE" json:"lots,omitempty"`
+	Reputation     *Reputation `gorm:"-" json:"reputation,omitempty"`
 }
@@ -6,12 +6,13 @@ import (
 )
 
 type BidHistory struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
-	BidID       uuid.UUID `gorm:"type:uuid;not null"`
-	Name        string    `gorm:"type:varchar(100);not null"`
-	Description string    `gorm:"type:varchar(500);not null"`
-	Status      BidStatus `gorm:"type:bid_status;not null"`
-	Version     int       `gorm:"type:int"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	Bid         Bid       `gorm:"foreignKey:BidID;references:ID;constraint:OnDelete:CASCADE"`
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	BidID       uuid.UUID `gorm:"type:uuid;not null" json:"bidId"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:varchar(500);not null" json:"description"`
+	Status      BidStatus `gorm:"type:bid_status;not null" json:"status"`
+	Price       *float64  `gorm:"type:numeric(15,2)" json:"price,omitempty"`
+	Version     int       `gorm:"type:int" json:"version"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	Bid         Bid       `gorm:"foreignKey:BidID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type BidLotStatus string
+
+const (
+	BidLotPending  BidLotStatus = "Pending"
+	BidLotApproved BidLotStatus = "Approved"
+	BidLotRejected BidLotStatus = "Rejected"
+)
+
+// BidLot - цена предложения по одному из лотов тендера
+type BidLot struct {
+	ID     uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	BidID  uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_bid_lot" json:"bidId"`
+	LotID  uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_bid_lot" json:"lotId"`
+	Price  float64      `gorm:"type:numeric(15,2);not null" json:"price"`
+	Status BidLotStatus `gorm:"type:varchar(20);not null;default:'Pending'" json:"status"`
+}
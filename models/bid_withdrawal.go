@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BidWithdrawal - запись об отзыве предложения. При повторной подаче запись сохраняется,
+// в ней лишь отмечается версия, с которой предложение вернулось в тендер
+type BidWithdrawal struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	BidID              uuid.UUID  `gorm:"type:uuid;not null;index" json:"bidId"`
+	AuthorID           uuid.UUID  `gorm:"type:uuid;not null" json:"authorId"`
+	Reason             string     `gorm:"type:varchar(500);not null" json:"reason"`
+	BidVersion         int        `gorm:"type:int;not null" json:"bidVersion"`
+	ResubmittedVersion *int       `gorm:"type:int" json:"resubmittedVersion,omitempty"`
+	ResubmittedAt      *time.Time `json:"resubmittedAt,omitempty"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	Bid                Bid        `gorm:"foreignKey:BidID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContractStatus string
+
+const (
+	ContractDraft            ContractStatus = "draft"
+	ContractSignedByBuyer    ContractStatus = "signed_by_buyer"
+	ContractSignedBySupplier ContractStatus = "signed_by_supplier"
+	ContractActive           ContractStatus = "active"
+	ContractCompleted        ContractStatus = "completed"
+)
+
+// Contract - договор по итогам тендера. Условия копируются из тендера и победившего предложения
+// с фиксацией их версий, поэтому последующие правки тендера и предложения договор не меняют
+type Contract struct {
+	ID                     uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID               uuid.UUID      `gorm:"type:uuid;not null;index" json:"tenderId"`
+	TenderVersion          int            `gorm:"type:int;not null" json:"tenderVersion"`
+	BidID                  uuid.UUID      `gorm:"type:uuid;not null;index" json:"bidId"`
+	BidVersion             int            `gorm:"type:int;not null" json:"bidVersion"`
+	LotID                  *uuid.UUID     `gorm:"type:uuid" json:"lotId,omitempty"`
+	OrganizationID         uuid.UUID      `gorm:"type:uuid;not null" json:"organizationId"`
+	SupplierType           AuthorType     `gorm:"type:author_type;not null" json:"supplierType"`
+	SupplierID             uuid.UUID      `gorm:"type:uuid;not null" json:"supplierId"`
+	SupplierOrganizationID *uuid.UUID     `gorm:"type:uuid" json:"supplierOrganizationId,omitempty"`
+	TenderName             string         `gorm:"type:varchar(100);not null" json:"tenderName"`
+	TenderDescription      string         `gorm:"type:varchar(500);not null" json:"tenderDescription"`
+	ServiceType            ServiceType    `gorm:"type:service_type;not null" json:"serviceType"`
+	LotName                string         `gorm:"type:varchar(100)" json:"lotName,omitempty"`
+	BidName                string         `gorm:"type:varchar(100);not null" json:"bidName"`
+	BidDescription         string         `gorm:"type:varchar(500);not null" json:"bidDescription"`
+	Price                  *float64       `gorm:"type:numeric(15,2)" json:"price,omitempty"`
+	Status                 ContractStatus `gorm:"type:varchar(20);not null" json:"status"`
+	BuyerSignedBy          *uuid.UUID     `gorm:"type:uuid" json:"buyerSignedBy,omitempty"`
+	BuyerSignedAt          *time.Time     `json:"buyerSignedAt,omitempty"`
+	SupplierSignedBy       *uuid.UUID     `gorm:"type:uuid" json:"supplierSignedBy,omitempty"`
+	SupplierSignedAt       *time.Time     `json:"supplierSignedAt,omitempty"`
+	CompletedAt            *time.Time     `json:"completedAt,omitempty"`
+	CreatedAt              time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt              time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+}
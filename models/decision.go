@@ -15,6 +15,7 @@ const (
 type Decision struct {
 	ID           uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
 	BidID        uuid.UUID    `gorm:"type:uuid;not null" json:"bidId"`
+	LotID        *uuid.UUID   `gorm:"type:uuid" json:"lotId,omitempty"`
 	AuthorID     uuid.UUID    `gorm:"type:uuid;not null" json:"authorId"`
 	DecisionType DecisionType `gorm:"type:decision_type;not null" json:"decisionType"`
 	CreatedAt    time.Time    `gorm:"autoCreateTime" json:"createdAt"`
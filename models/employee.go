@@ -11,6 +11,7 @@ type Employee struct {
 	Username     string                    `gorm:"type:varchar(50);unique;not null" json:"username"`
 	FirstName    string                    `gorm:"type:varchar(50)" json:"firstName"`
 	LastName     string                    `gorm:"type:varchar(50)" json:"lastName"`
+	Email        string                    `gorm:"type:varchar(255)" json:"email"`
 	CreatedAt    time.Time                 `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt    time.Time                 `gorm:"autoUpdateTime" json:"updatedAt"`
 	Organization []OrganizationResponsible `gorm:"foreignKey:UserID;references:ID;"`
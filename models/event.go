@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventTenderStatus EventType = "tender_status"
+	EventBidPublished EventType = "bid_published"
+	EventDecision     EventType = "decision"
+	EventFeedback     EventType = "feedback"
+	EventAuctionRound EventType = "auction_round"
+	EventAuctionPrice EventType = "auction_price"
+	EventBidWithdrawn EventType = "bid_withdrawn"
+)
+
+type Event struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type           EventType  `gorm:"type:varchar(50);not null" json:"type"`
+	TenderID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenderId"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null" json:"organizationId"`
+	BidID          *uuid.UUID `gorm:"type:uuid" json:"bidId,omitempty"`
+	BidAuthorID    *uuid.UUID `gorm:"type:uuid" json:"-"`
+	Payload        string     `gorm:"type:jsonb;not null;default:'{}'" json:"-"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
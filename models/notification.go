@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationBidDecision  NotificationType = "bid_decision"
+	NotificationBidFeedback  NotificationType = "bid_feedback"
+	NotificationTenderClosed NotificationType = "tender_closed"
+	NotificationNewBid       NotificationType = "new_bid"
+	NotificationReviewReply  NotificationType = "review_reply"
+)
+
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index" json:"-"`
+	Type      NotificationType `gorm:"type:varchar(50);not null" json:"type"`
+	Title     string           `gorm:"type:varchar(255);not null" json:"title"`
+	TenderID  *uuid.UUID       `gorm:"type:uuid" json:"tenderId,omitempty"`
+	BidID     *uuid.UUID       `gorm:"type:uuid" json:"bidId,omitempty"`
+	Read      bool             `gorm:"not null;default:false" json:"read"`
+	ReadAt    *time.Time       `json:"readAt,omitempty"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+	User      Employee         `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationPreference struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"-"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;unique" json:"-"`
+	Locale       string    `gorm:"type:varchar(2);not null" json:"locale"`
+	EmailEnabled bool      `gorm:"not null" json:"emailEnabled"`
+	BidDecision  bool      `gorm:"not null" json:"bidDecision"`
+	BidFeedback  bool      `gorm:"not null" json:"bidFeedback"`
+	TenderClosed bool      `gorm:"not null" json:"tenderClosed"`
+	NewBid       bool      `gorm:"not null" json:"newBid"`
+	ReviewReply  bool      `gorm:"not null;default:true" json:"reviewReply"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+	User         Employee  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// DefaultNotificationPreference - настройки пользователя, который их ещё не менял
+func DefaultNotificationPreference(userID uuid.UUID) NotificationPreference {
+	return NotificationPreference{
+		UserID:       userID,
+		Locale:       "ru",
+		EmailEnabled: true,
+		BidDecision:  true,
+		BidFeedback:  true,
+		TenderClosed: true,
+		NewBid:       true,
+		ReviewReply:  true,
+	}
+}
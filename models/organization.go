@@ -21,5 +21,5 @@ type Organization struct {
 	Type        OrganizationType          `gorm:"type:organization_type" json:"type"`
 	CreatedAt   time.Time                 `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt   time.Time                 `gorm:"autoUpdateTime" json:"updatedAt"`
-	Employees   []OrganizationResponsible `gorm:"foreignKey:OrganizationID;references:ID;"`
+	Employees   []OrganizationResponsible `gorm:"foreignKey:OrganizationID;references:ID;" json:"-"`
 }
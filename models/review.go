@@ -6,9 +6,24 @@ import (
 )
 
 type Review struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
-	BidID       uuid.UUID `gorm:"type:uuid;not null" json:"bidId"`
-	BidAuthorID uuid.UUID `gorm:"type:uuid;not null" json:"bidAuthorId"`
-	Description string    `gorm:"type:varchar(1000);not null" json:"description"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	ID            uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	BidID         uuid.UUID    `gorm:"type:uuid;not null" json:"bidId"`
+	ReviewerID    uuid.UUID    `gorm:"type:uuid;not null" json:"reviewerId"`
+	BidAuthorID   uuid.UUID    `gorm:"type:uuid;not null;index" json:"bidAuthorId"`
+	Description   string       `gorm:"type:varchar(1000);not null" json:"description"`
+	Quality       *int         `gorm:"type:smallint" json:"quality,omitempty"`
+	Timeliness    *int         `gorm:"type:smallint" json:"timeliness,omitempty"`
+	Communication *int         `gorm:"type:smallint" json:"communication,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updatedAt"`
+	Reply         *ReviewReply `gorm:"foreignKey:ReviewID;references:ID" json:"reply,omitempty"`
+}
+
+// Reputation - средние оценки автора предложения или организации по всем отзывам с оценками
+type Reputation struct {
+	Reviews       int64   `json:"reviews"`
+	Quality       float64 `json:"quality"`
+	Timeliness    float64 `json:"timeliness"`
+	Communication float64 `json:"communication"`
+	Score         float64 `json:"score"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewReply - ответ автора предложения на отзыв. На каждый отзыв допускается один ответ
+type ReviewReply struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	ReviewID  uuid.UUID `gorm:"type:uuid;not null;unique" json:"reviewId"`
+	AuthorID  uuid.UUID `gorm:"type:uuid;not null" json:"authorId"`
+	Text      string    `gorm:"type:varchar(1000);not null" json:"text"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	Review    Review    `gorm:"foreignKey:ReviewID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
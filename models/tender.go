@@ -21,6 +21,13 @@ const (
 	Closed    Status = "Closed"
 )
 
+type Visibility string
+
+const (
+	VisibilityPublic     Visibility = "public"
+	VisibilityInviteOnly Visibility = "invite_only"
+)
+
 type Tender struct {
 	ID             uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
 	Name           string      `gorm:"type:varchar(100);not null" json:"name"`
@@ -29,5 +36,10 @@ type Tender struct {
 	Status         Status      `gorm:"type:status;not null" json:"status"`
 	OrganizationID uuid.UUID   `gorm:"type:uuid;not null" json:"organizationId"`
 	Version        int         `gorm:"type:int;default:1" json:"version"`
+	Sealed         bool        `gorm:"not null;default:false" json:"sealed"`
+	Deadline       *time.Time  `json:"deadline,omitempty"`
+	AuctionMode    bool        `gorm:"not null;default:false" json:"auctionMode"`
+	Visibility     Visibility  `gorm:"type:varchar(20);not null;default:'public'" json:"visibility"`
 	CreatedAt      time.Time   `gorm:"autoCreateTime" json:"createdAt"`
+	Lots           []TenderLot `gorm:"foreignKey:TenderID;references:ID;constraint:OnDelete:CASCADE" json:"lots,omitempty"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenderInvitation - приглашение к участию в закрытом тендере. Приглашается либо конкретный сотрудник,
+// либо организация целиком: тогда приглашёнными считаются все её ответственные лица
+type TenderInvitation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenderId"`
+	EmployeeID     *uuid.UUID `gorm:"type:uuid" json:"employeeId,omitempty"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organizationId,omitempty"`
+	InvitedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"invitedBy"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	Tender         Tender     `gorm:"foreignKey:TenderID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
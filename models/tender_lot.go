@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LotStatus string
+
+const (
+	LotOpen    LotStatus = "Open"
+	LotAwarded LotStatus = "Awarded"
+)
+
+type TenderLot struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenderId"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	Description string     `gorm:"type:varchar(500);not null" json:"description"`
+	Budget      float64    `gorm:"type:numeric(15,2);not null" json:"budget"`
+	Quantity    int        `gorm:"type:int;not null" json:"quantity"`
+	Status      LotStatus  `gorm:"type:varchar(20);not null;default:'Open'" json:"status"`
+	WinnerBidID *uuid.UUID `gorm:"type:uuid" json:"winnerBidId,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
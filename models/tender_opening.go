@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OpeningReason string
+
+const (
+	OpeningManual   OpeningReason = "manual"
+	OpeningDeadline OpeningReason = "deadline"
+)
+
+// TenderOpening - запись о вскрытии запечатанных предложений тендера
+type TenderOpening struct {
+	ID        uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID  uuid.UUID     `gorm:"type:uuid;not null;unique" json:"tenderId"`
+	OpenedBy  *uuid.UUID    `gorm:"type:uuid" json:"openedBy,omitempty"`
+	Reason    OpeningReason `gorm:"type:varchar(20);not null" json:"reason"`
+	CreatedAt time.Time     `gorm:"autoCreateTime" json:"createdAt"`
+	Tender    Tender        `gorm:"foreignKey:TenderID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
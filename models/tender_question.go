@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TenderQuestion struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primary_key" json:"id"`
+	TenderID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenderId"`
+	AuthorID      uuid.UUID  `gorm:"type:uuid;not null" json:"authorId"`
+	Question      string     `gorm:"type:varchar(1000);not null" json:"question"`
+	Answer        *string    `gorm:"type:varchar(2000)" json:"answer,omitempty"`
+	AnsweredBy    *uuid.UUID `gorm:"type:uuid" json:"answeredBy,omitempty"`
+	AnsweredAt    *time.Time `json:"answeredAt,omitempty"`
+	Public        bool       `gorm:"not null;default:false" json:"public"`
+	TenderVersion *int       `gorm:"type:int" json:"tenderVersion,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	Tender        Tender     `gorm:"foreignKey:TenderID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,5 @@
+package notify
+
+type FakeSMTP = fakeSMTP
+
+var StartFakeSMTP = startFakeSMTP
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier доставляет сообщение получателю
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier только пишет сообщения в журнал. Используется, когда SMTP не настроен
+type LogNotifier struct{}
+
+func (LogNotifier) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "Notification", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,103 @@
+package notify_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"myapp/config"
+	"myapp/database"
+	"myapp/health"
+	"myapp/models"
+	"myapp/notify"
+	"myapp/router"
+)
+
+// TestPreferencesOptOut проверяет, что отписка через PUT /api/notifications/preferences отключает письма,
+// но не записи во входящих. Без TEST_POSTGRES_CONN тест пропускается
+func TestPreferencesOptOut(t *testing.T) {
+	conn := os.Getenv("TEST_POSTGRES_CONN")
+	if conn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+
+	cfg, err := config.ReadConfig("")
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	cfg.DB.Conn = conn
+	cfg.HTTP.GinMode = gin.TestMode
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.Open(cfg.DB)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	suffix := uuid.NewString()[:8]
+	subscribed := models.Employee{Username: "subscribed-" + suffix, Email: "subscribed-" + suffix + "@example.com"}
+	optedOut := models.Employee{Username: "opted-out-" + suffix, Email: "opted-out-" + suffix + "@example.com"}
+	for _, employee := range []*models.Employee{&subscribed, &optedOut} {
+		if err := db.Create(employee).Error; err != nil {
+			t.Fatalf("create employee: %v", err)
+		}
+	}
+
+	smtp := notify.StartFakeSMTP(t)
+	svc := notify.NewService(smtp.Notifier(), 4)
+	svc.Start(1)
+
+	r := router.SetupRouter(cfg, db, nil, svc, nil, nil, health.NewChecker(time.Second))
+	req := httptest.NewRequest(http.MethodPut, "/api/notifications/preferences?username="+optedOut.Username, strings.NewReader(`{"bidDecision": false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update preferences: status %d: %s", w.Code, w.Body)
+	}
+
+	tender := models.Tender{Name: "Ремонт офиса"}
+	decision := models.Decision{DecisionType: models.Approved}
+	for _, employee := range []models.Employee{subscribed, optedOut} {
+		bid := models.Bid{Name: "Ремонт", AuthorID: employee.ID}
+		if err := svc.BidDecision(db, bid, tender, decision); err != nil {
+			t.Fatalf("notify %s: %v", employee.Username, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := svc.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	mails := smtp.Mails()
+	if len(mails) != 1 || mails[0].To != subscribed.Email {
+		t.Fatalf("delivered %v, want one mail to %s", mails, subscribed.Email)
+	}
+	if subject, _ := mails[0].Parse(t); subject != "Решение по предложению «Ремонт»" {
+		t.Errorf("subject = %q", subject)
+	}
+
+	var inbox int64
+	if err := db.Model(&models.Notification{}).Where("user_id = ?", optedOut.ID).Count(&inbox).Error; err != nil {
+		t.Fatalf("count notifications: %v", err)
+	}
+	if inbox != 1 {
+		t.Errorf("opted-out user has %d notifications, want 1", inbox)
+	}
+}
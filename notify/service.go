@@ -0,0 +1,272 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"myapp/health"
+	"myapp/models"
+)
+
+const (
+	sendAttempts = 3
+	sendTimeout  = 30 * time.Second
+
+	// Одно событие может рассылаться многим получателям с повторами, поэтому порог зависания с запасом
+	stallTimeout = 5 * time.Minute
+)
+
+// job - письмо, ожидающее отправки. Записи во входящих создаются сразу при событии,
+// а в очередь попадает только отправка писем, которая может занимать секунды
+type job struct {
+	kind Kind
+	msg  Message
+}
+
+// Service сохраняет уведомления во входящие пользователей вместе с изменением, которое их вызвало,
+// и в фоне рассылает письма
+type Service struct {
+	notifier Notifier
+	jobs     chan job
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	closed   bool
+	progress health.Heartbeat
+}
+
+func NewService(notifier Notifier, queueSize int) *Service {
+	return &Service{
+		notifier: notifier,
+		jobs:     make(chan job, queueSize),
+	}
+}
+
+func (s *Service) Start(workers int) {
+	s.progress.Beat()
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for j := range s.jobs {
+				s.send(j.msg)
+				s.progress.Beat()
+			}
+		}()
+	}
+}
+
+// Stop перестаёт принимать новые письма и ждёт отправки уже поставленных в очередь
+func (s *Service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.jobs)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check сообщает об ошибке, если в очереди есть письма, а обработчики давно не отправили ни одного
+func (s *Service) Check(context.Context) error {
+	queued := len(s.jobs)
+	if queued == 0 {
+		return nil
+	}
+
+	since, ok := s.progress.Since()
+	if !ok {
+		return fmt.Errorf("%d notifications queued, workers are not started", queued)
+	}
+	if since > stallTimeout {
+		return fmt.Errorf("%d notifications queued, workers made no progress for %s", queued, since.Round(time.Second))
+	}
+	return nil
+}
+
+// enqueue ставит письмо в очередь. Переполнение очереди или остановка сервиса теряют только письмо:
+// запись во входящих к этому моменту уже сохранена
+func (s *Service) enqueue(j job) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		slog.Warn("Notification service is stopped, email dropped", "kind", j.kind, "to", j.msg.To)
+		return
+	}
+
+	select {
+	case s.jobs <- j:
+	default:
+		slog.Warn("Notification queue is full, email dropped", "kind", j.kind, "to", j.msg.To)
+	}
+}
+
+// Методы уведомлений вызываются при событии с тем же db, что и изменение, - обычно с транзакцией запроса.
+// Ошибка сохранения входящих возвращается вызывающему, чтобы он мог отменить транзакцию или записать её в журнал
+
+func (s *Service) BidDecision(db *gorm.DB, bid models.Bid, tender models.Tender, decision models.Decision) error {
+	return s.notify(db, KindBidDecision, Data{Tender: tender, Bid: bid, Decision: decision}, []uuid.UUID{bid.AuthorID})
+}
+
+func (s *Service) BidFeedback(db *gorm.DB, bid models.Bid, tender models.Tender, review models.Review) error {
+	return s.notify(db, KindBidFeedback, Data{Tender: tender, Bid: bid, Review: review}, []uuid.UUID{bid.AuthorID})
+}
+
+func (s *Service) TenderClosed(db *gorm.DB, tender models.Tender) error {
+	var ids []uuid.UUID
+	if err := db.Model(&models.Bid{}).Distinct("author_id").Where("tender_id = ?", tender.ID).Pluck("author_id", &ids).Error; err != nil {
+		return err
+	}
+
+	return s.notify(db, KindTenderClosed, Data{Tender: tender}, ids)
+}
+
+func (s *Service) NewBid(db *gorm.DB, bid models.Bid) error {
+	data := Data{Bid: bid}
+	if err := db.Where("id = ?", bid.TenderID).First(&data.Tender).Error; err != nil {
+		return err
+	}
+
+	var ids []uuid.UUID
+	if err := db.Model(&models.OrganizationResponsible{}).Where("organization_id = ?", data.Tender.OrganizationID).Pluck("user_id", &ids).Error; err != nil {
+		return err
+	}
+
+	return s.notify(db, KindNewBid, data, ids)
+}
+
+// ReviewReply оповещает автора отзыва об ответе автора предложения
+func (s *Service) ReviewReply(db *gorm.DB, bid models.Bid, tender models.Tender, review models.Review, reply models.ReviewReply) error {
+	return s.notify(db, KindReviewReply, Data{Tender: tender, Bid: bid, Review: review, Reply: reply}, []uuid.UUID{review.ReviewerID})
+}
+
+// notify сохраняет записи во входящих получателей на их языке и ставит в очередь письма тем,
+// кто подписан на уведомления этого типа
+func (s *Service) notify(db *gorm.DB, kind Kind, data Data, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var employees []models.Employee
+	if err := db.Where("id IN ?", ids).Find(&employees).Error; err != nil {
+		return fmt.Errorf("load notification recipients: %w", err)
+	}
+
+	var prefs []models.NotificationPreference
+	if err := db.Where("user_id IN ?", ids).Find(&prefs).Error; err != nil {
+		return fmt.Errorf("load notification preferences: %w", err)
+	}
+
+	prefByUser := make(map[uuid.UUID]models.NotificationPreference, len(prefs))
+	for _, pref := range prefs {
+		prefByUser[pref.UserID] = pref
+	}
+
+	var tenderID, bidID *uuid.UUID
+	if data.Tender.ID != uuid.Nil {
+		tenderID = &data.Tender.ID
+	}
+	if data.Bid.ID != uuid.Nil {
+		bidID = &data.Bid.ID
+	}
+
+	var inbox []models.Notification
+	var emails []Message
+	for _, employee := range employees {
+		pref, ok := prefByUser[employee.ID]
+		if !ok {
+			pref = models.DefaultNotificationPreference(employee.ID)
+		}
+
+		d := data
+		d.Recipient = employee
+
+		subject, body, err := Render(pref.Locale, kind, d)
+		if err != nil {
+			return fmt.Errorf("render %s notification: %w", kind, err)
+		}
+
+		// Запись во входящих создаётся независимо от настроек рассылки писем
+		inbox = append(inbox, models.Notification{
+			UserID:   employee.ID,
+			Type:     kind,
+			Title:    subject,
+			TenderID: tenderID,
+			BidID:    bidID,
+		})
+
+		if employee.Email != "" && wants(pref, kind) {
+			emails = append(emails, Message{To: employee.Email, Subject: subject, Body: body})
+		}
+	}
+
+	if len(inbox) > 0 {
+		if err := db.Omit("User").Create(&inbox).Error; err != nil {
+			return fmt.Errorf("save notifications: %w", err)
+		}
+	}
+
+	for _, msg := range emails {
+		s.enqueue(job{kind: kind, msg: msg})
+	}
+
+	return nil
+}
+
+func (s *Service) send(msg Message) {
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err = s.notifier.Send(ctx, msg)
+		cancel()
+
+		if err == nil {
+			return
+		}
+
+		if attempt < sendAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+
+	slog.Error("Failed to send notification", "to", msg.To, "error", err)
+}
+
+// wants проверяет, подписан ли пользователь на уведомления данного типа
+func wants(pref models.NotificationPreference, kind Kind) bool {
+	if !pref.EmailEnabled {
+		return false
+	}
+
+	switch kind {
+	case KindBidDecision:
+		return pref.BidDecision
+	case KindBidFeedback:
+		return pref.BidFeedback
+	case KindTenderClosed:
+		return pref.TenderClosed
+	case KindNewBid:
+		return pref.NewBid
+	case KindReviewReply:
+		return pref.ReviewReply
+	}
+
+	return false
+}
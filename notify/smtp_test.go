@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"myapp/models"
+)
+
+// fakeMail - письмо, принятое fakeSMTP
+type fakeMail struct {
+	To   string
+	Data string
+}
+
+// fakeSMTP - минимальный SMTP-сервер на 127.0.0.1 без STARTTLS и авторизации, сохраняющий принятые письма
+type fakeSMTP struct {
+	listener net.Listener
+	mails    chan fakeMail
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &fakeSMTP{listener: listener, mails: make(chan fakeMail, 16)}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Notifier возвращает SMTPNotifier, отправляющий письма на этот сервер
+func (s *fakeSMTP) Notifier() SMTPNotifier {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPNotifier{Host: addr.IP.String(), Port: addr.Port, From: "noreply@example.com"}
+}
+
+// Mails возвращает письма, принятые к этому моменту
+func (s *fakeSMTP) Mails() []fakeMail {
+	var mails []fakeMail
+	for {
+		select {
+		case m := <-s.mails:
+			mails = append(mails, m)
+		default:
+			return mails
+		}
+	}
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	var to string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			to = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.mails <- fakeMail{To: to, Data: data.String()}
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// Parse разбирает письмо и возвращает декодированные тему и текст. Тема должна быть закодирована Q-кодированием
+func (m fakeMail) Parse(t *testing.T) (subject, body string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(m.Data))
+	if err != nil {
+		t.Fatalf("parse mail: %v", err)
+	}
+
+	raw := msg.Header.Get("Subject")
+	if !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Errorf("subject %q is not Q-encoded", raw)
+	}
+	subject, err = new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+
+	encoded, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return subject, string(decoded)
+}
+
+func TestServiceSendsQueuedMail(t *testing.T) {
+	smtp := startFakeSMTP(t)
+	s := NewService(smtp.Notifier(), 4)
+	s.Start(1)
+
+	want := Message{To: "supplier@example.com", Subject: "Решение по предложению «Ремонт»", Body: "Предложение одобрено"}
+	s.enqueue(job{kind: KindBidDecision, msg: want})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	mails := smtp.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("delivered %d mails, want 1", len(mails))
+	}
+	if mails[0].To != want.To {
+		t.Errorf("recipient = %q, want %q", mails[0].To, want.To)
+	}
+
+	subject, body := mails[0].Parse(t)
+	if subject != want.Subject || body != want.Body {
+		t.Errorf("mail = %q / %q, want %q / %q", subject, body, want.Subject, want.Body)
+	}
+}
+
+func TestWants(t *testing.T) {
+	pref := models.DefaultNotificationPreference(uuid.New())
+	if !wants(pref, KindBidDecision) {
+		t.Error("default preferences do not subscribe to bid decisions")
+	}
+
+	pref.BidDecision = false
+	if wants(pref, KindBidDecision) || !wants(pref, KindNewBid) {
+		t.Error("opting out of bid decisions must not affect other kinds")
+	}
+
+	pref.EmailEnabled = false
+	if wants(pref, KindNewBid) {
+		t.Error("disabled email still subscribes to new bids")
+	}
+}
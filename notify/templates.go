@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"myapp/models"
+)
+
+// Kind совпадает с типом уведомления во входящих, чтобы одно событие порождало и письмо, и запись
+type Kind = models.NotificationType
+
+const (
+	KindBidDecision  = models.NotificationBidDecision
+	KindBidFeedback  = models.NotificationBidFeedback
+	KindTenderClosed = models.NotificationTenderClosed
+	KindNewBid       = models.NotificationNewBid
+	KindReviewReply  = models.NotificationReviewReply
+)
+
+const defaultLocale = "ru"
+
+// Data - данные, доступные в шаблонах сообщений
+type Data struct {
+	Recipient models.Employee
+	Tender    models.Tender
+	Bid       models.Bid
+	Decision  models.Decision
+	Review    models.Review
+	Reply     models.ReviewReply
+}
+
+//go:embed templates/*/*.tmpl
+var templateFS embed.FS
+
+// Шаблоны по языку и типу события
+var templates = map[string]map[Kind]*template.Template{}
+
+func init() {
+	for _, locale := range []string{"ru", "en"} {
+		templates[locale] = map[Kind]*template.Template{}
+		for _, kind := range []Kind{KindBidDecision, KindBidFeedback, KindTenderClosed, KindNewBid, KindReviewReply} {
+			path := fmt.Sprintf("templates/%s/%s.tmpl", locale, kind)
+			templates[locale][kind] = template.Must(template.ParseFS(templateFS, path))
+		}
+	}
+}
+
+// Render формирует тему и текст сообщения на языке получателя
+func Render(locale string, kind Kind, data Data) (subject, body string, err error) {
+	set, ok := templates[locale]
+	if !ok {
+		set = templates[defaultLocale]
+	}
+
+	tmpl, ok := set[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	body = strings.TrimSpace(buf.String())
+
+	return subject, body, nil
+}
+
+// SupportedLocale проверяет, есть ли шаблоны для указанного языка
+func SupportedLocale(locale string) bool {
+	_, ok := templates[locale]
+	return ok
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Check сверяет маршруты gin со спецификацией: каждый маршрут должен быть описан, а каждая операция
+// спецификации - обслуживаться маршрутом. Маршрут с параметром *action ничего не доказывает о своих действиях,
+// поэтому считается ошибкой: его нужно заменить маршрутами действий (router.Routes)
+func Check(routes gin.RoutesInfo) error {
+	spec, err := Spec()
+	if err != nil {
+		return err
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+normalize(path)] = false
+		}
+	}
+
+	var errs []error
+	for _, route := range routes {
+		key := route.Method + " " + normalize(route.Path)
+
+		if strings.HasSuffix(key, "*") {
+			errs = append(errs, fmt.Errorf("route %s %s has a wildcard, list its actions in the router", route.Method, route.Path))
+			continue
+		}
+		if _, ok := documented[key]; !ok {
+			errs = append(errs, fmt.Errorf("route %s %s is not documented", route.Method, route.Path))
+			continue
+		}
+		documented[key] = true
+	}
+
+	var unrouted []string
+	for op, routed := range documented {
+		if !routed {
+			unrouted = append(unrouted, op)
+		}
+	}
+	sort.Strings(unrouted)
+	for _, op := range unrouted {
+		errs = append(errs, fmt.Errorf("operation %s has no route", op))
+	}
+
+	return errors.Join(errs...)
+}
+
+// normalize приводит пути gin (/bids/:bidID/*action) и OpenAPI (/bids/{bidId}) к общему виду (/bids/{}/*),
+// так как имена параметров в них могут отличаться
+func normalize(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"), strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+			segments[i] = "{}"
+		case strings.HasPrefix(s, "*"):
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
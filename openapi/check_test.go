@@ -0,0 +1,61 @@
+package openapi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"myapp/config"
+	"myapp/health"
+	"myapp/openapi"
+	"myapp/router"
+)
+
+func routes(t *testing.T) gin.RoutesInfo {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg, err := config.ReadConfig("")
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	cfg.HTTP.GinMode = gin.TestMode
+
+	r := router.SetupRouter(cfg, nil, nil, nil, nil, nil, health.NewChecker(time.Second))
+	return router.Routes(r)
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	if err := openapi.Check(routes(t)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckReportsMismatches(t *testing.T) {
+	var broken gin.RoutesInfo
+	for _, route := range routes(t) {
+		if route.Method == "GET" && route.Path == "/api/bids/:id/status" {
+			continue
+		}
+		broken = append(broken, route)
+	}
+	broken = append(broken,
+		gin.RouteInfo{Method: "GET", Path: "/api/bids/:id/undocumented"},
+		gin.RouteInfo{Method: "GET", Path: "/api/tenders/:tenderId/*action"},
+	)
+
+	err := openapi.Check(broken)
+	if err == nil {
+		t.Fatal("Check accepted mismatched routes")
+	}
+	for _, want := range []string{
+		"operation GET /api/bids/{}/status has no route",
+		"route GET /api/bids/:id/undocumented is not documented",
+		"route GET /api/tenders/:tenderId/*action has a wildcard",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not report %q:\n%v", want, err)
+		}
+	}
+}
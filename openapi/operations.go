@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"myapp/controllers"
+	"myapp/graph"
+	"myapp/health"
+	"myapp/models"
+)
+
+func pathParam(name, description string) *openapi3.Parameter {
+	return openapi3.NewPathParameter(name).WithDescription(description).
+		WithSchema(openapi3.NewStringSchema().WithFormat("uuid"))
+}
+
+func query(name, description string, schema *openapi3.Schema) *openapi3.Parameter {
+	return openapi3.NewQueryParameter(name).WithDescription(description).WithSchema(schema)
+}
+
+func required(p *openapi3.Parameter) *openapi3.Parameter {
+	return p.WithRequired(true)
+}
+
+func enumOf(values ...any) *openapi3.Schema {
+	return openapi3.NewStringSchema().WithEnum(values...)
+}
+
+// UnreadCount и UpdatedCount описывают ответы, которые контроллеры формируют через gin.H
+type UnreadCount struct {
+	Unread int64 `json:"unread"`
+}
+
+type UpdatedCount struct {
+	Updated int64 `json:"updated"`
+}
+
+// GraphQLResponse описывает ответ POST /api/graphql. Форма data повторяет запрос, поэтому её схема не фиксирована;
+// data равно null, если запрос не прошёл проверку по схеме GraphQL
+type GraphQLResponse struct {
+	Data   *map[string]any `json:"data"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+// GraphQLError - ошибка запроса или отдельного поля; для ошибок полей extensions.code содержит BAD_REQUEST,
+// FORBIDDEN, NOT_FOUND или INTERNAL, а message - ту же причину, что reason в REST API
+type GraphQLError struct {
+	Message    string            `json:"message"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`
+	Path       []any             `json:"path,omitempty"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+var (
+	tenderID       = pathParam("tenderId", "Идентификатор тендера")
+	bidID          = pathParam("bidId", "Идентификатор предложения")
+	attachmentID   = pathParam("attachmentId", "Идентификатор вложения")
+	invitationID   = pathParam("invitationId", "Идентификатор приглашения")
+	questionID     = pathParam("questionId", "Идентификатор вопроса")
+	reviewID       = pathParam("reviewId", "Идентификатор отзыва")
+	contractID     = pathParam("contractId", "Идентификатор договора")
+	notificationID = pathParam("notificationId", "Идентификатор уведомления")
+	version        = openapi3.NewPathParameter("version").WithDescription("Версия, к которой выполняется откат").
+			WithSchema(openapi3.NewIntegerSchema().WithMin(1))
+
+	username    = required(query("username", "Пользователь, выполняющий запрос", openapi3.NewStringSchema()))
+	optUsername = query("username", "Пользователь, выполняющий запрос", openapi3.NewStringSchema())
+	limit       = query("limit", "Размер страницы; большие значения уменьшаются до максимального", openapi3.NewIntegerSchema().WithMin(1))
+	offset      = query("offset", "Число пропускаемых записей", openapi3.NewIntegerSchema().WithMin(0))
+	lotID       = query("lotId", "Идентификатор лота", openapi3.NewStringSchema().WithFormat("uuid"))
+	fileVersion = query("version", "Версия тендера или предложения, для которой возвращаются вложения", openapi3.NewIntegerSchema().WithMin(1))
+
+	tenderStatus = enumOf(models.Created, models.Published, models.Closed)
+	bidStatus    = enumOf(models.BidCreated, models.BidPublished, models.BidCanceled)
+)
+
+// operations - все маршруты сервиса. Новый маршрут в router.SetupRouter должен быть описан здесь,
+// иначе команда openapi check завершится ошибкой
+var operations = []operation{
+	// Служебные маршруты
+	{method: "GET", path: "/healthz", id: "liveness", tag: "service", summary: "Проверка, что процесс жив",
+		response: map[string]string{}},
+	{method: "GET", path: "/readyz", id: "readiness", tag: "service", summary: "Готовность принимать запросы; 503, если зависимость недоступна",
+		response: health.Report{}},
+	{method: "GET", path: "/api/ping", id: "ping", tag: "service", summary: "Проверка доступности сервера",
+		content: "text/plain"},
+	{method: "GET", path: "/api/openapi.json", id: "getOpenAPI", tag: "service", summary: "Эта спецификация",
+		response: map[string]any{}},
+	{method: "GET", path: "/api/docs", id: "getDocs", tag: "service", summary: "Swagger UI",
+		content: "text/html"},
+
+	// Тендеры
+	{method: "GET", path: "/api/tenders", id: "getTenders", tag: "tenders",
+		summary: "Опубликованные тендеры; без username только публичные",
+		params: []*openapi3.Parameter{limit, offset, optUsername,
+			query("service_type", "Фильтр по виду услуг", openapi3.NewArraySchema().WithItems(enumOf(models.Construction, models.Delivery, models.Manufacture)))},
+		response: []models.Tender{}},
+	{method: "POST", path: "/api/tenders/new", id: "createTender", tag: "tenders", summary: "Создание тендера",
+		body: controllers.CreateTenderRequest{}, response: models.Tender{}},
+	{method: "GET", path: "/api/tenders/my", id: "getUserTenders", tag: "tenders", summary: "Тендеры организаций пользователя",
+		params: []*openapi3.Parameter{limit, offset, username}, response: []models.Tender{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/status", id: "getTenderStatus", tag: "tenders", summary: "Статус тендера",
+		params: []*openapi3.Parameter{tenderID, username}, response: models.Status("")},
+	{method: "PUT", path: "/api/tenders/{tenderId}/status", id: "updateTenderStatus", tag: "tenders", summary: "Изменение статуса тендера",
+		params:   []*openapi3.Parameter{tenderID, username, required(query("status", "Новый статус", tenderStatus))},
+		response: models.Tender{}},
+	{method: "PATCH", path: "/api/tenders/{tenderId}/edit", id: "editTender", tag: "tenders", summary: "Редактирование тендера",
+		params: []*openapi3.Parameter{tenderID, username}, body: controllers.UpdateTenderRequest{}, response: models.Tender{}},
+	{method: "PUT", path: "/api/tenders/{tenderId}/rollback/{version}", id: "rollbackTender", tag: "tenders", summary: "Откат тендера к версии",
+		params: []*openapi3.Parameter{tenderID, version, username}, response: models.Tender{}},
+	{method: "PUT", path: "/api/tenders/{tenderId}/open", id: "openTenderBids", tag: "tenders", summary: "Вскрытие предложений закрытого тендера",
+		params: []*openapi3.Parameter{tenderID, username}, response: models.TenderOpening{}},
+	{method: "POST", path: "/api/tenders/{tenderId}/lots", id: "createLot", tag: "tenders", summary: "Добавление лота",
+		params: []*openapi3.Parameter{tenderID, username}, body: controllers.CreateLotRequest{}, response: models.TenderLot{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/lots", id: "getLots", tag: "tenders", summary: "Лоты тендера",
+		params: []*openapi3.Parameter{tenderID, username}, response: []models.TenderLot{}},
+
+	// Аукционы
+	{method: "POST", path: "/api/tenders/{tenderId}/rounds", id: "openRound", tag: "auctions", summary: "Открытие раунда аукциона",
+		params: []*openapi3.Parameter{tenderID, username,
+			required(query("duration", "Длительность раунда, например 30m", openapi3.NewStringSchema()))},
+		response: models.AuctionRound{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/rounds", id: "getRounds", tag: "auctions", summary: "Раунды аукциона",
+		params: []*openapi3.Parameter{tenderID, username}, response: []models.AuctionRound{}},
+	{method: "PUT", path: "/api/bids/{bidId}/price", id: "lowerBidPrice", tag: "auctions", summary: "Снижение цены предложения в открытом раунде",
+		params: []*openapi3.Parameter{bidID, username,
+			required(query("price", "Новая цена", openapi3.NewFloat64Schema().WithExclusiveMin(true).WithMin(0)))},
+		response: models.Bid{}},
+
+	// Приглашения
+	{method: "POST", path: "/api/tenders/{tenderId}/invitations", id: "createInvitation", tag: "invitations", summary: "Приглашение пользователя или организации",
+		params: []*openapi3.Parameter{tenderID, username}, body: controllers.CreateInvitationRequest{}, response: models.TenderInvitation{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/invitations", id: "getInvitations", tag: "invitations", summary: "Приглашения тендера",
+		params: []*openapi3.Parameter{tenderID, username}, response: []models.TenderInvitation{}},
+	{method: "DELETE", path: "/api/tenders/{tenderId}/invitations/{invitationId}", id: "deleteInvitation", tag: "invitations", summary: "Отзыв приглашения",
+		params: []*openapi3.Parameter{tenderID, invitationID, username}, response: models.TenderInvitation{}},
+
+	// Договоры
+	{method: "POST", path: "/api/tenders/{tenderId}/contracts", id: "createContract", tag: "contracts", summary: "Создание договора по победившему предложению",
+		params: []*openapi3.Parameter{tenderID, username,
+			required(query("bidId", "Идентификатор предложения", openapi3.NewStringSchema().WithFormat("uuid"))), lotID},
+		response: models.Contract{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/contracts", id: "getTenderContracts", tag: "contracts", summary: "Договоры тендера",
+		params: []*openapi3.Parameter{tenderID, username}, response: []models.Contract{}},
+	{method: "GET", path: "/api/contracts/{contractId}", id: "getContract", tag: "contracts", summary: "Договор",
+		params: []*openapi3.Parameter{contractID, username}, response: models.Contract{}},
+	{method: "GET", path: "/api/contracts/{contractId}/pdf", id: "downloadContractPDF", tag: "contracts", summary: "Печатная форма договора",
+		params: []*openapi3.Parameter{contractID, username}, content: "application/pdf"},
+	{method: "PUT", path: "/api/contracts/{contractId}/sign/buyer", id: "signAsBuyer", tag: "contracts", summary: "Подписание заказчиком",
+		params: []*openapi3.Parameter{contractID, username}, response: models.Contract{}},
+	{method: "PUT", path: "/api/contracts/{contractId}/sign/supplier", id: "signAsSupplier", tag: "contracts", summary: "Подписание поставщиком",
+		params: []*openapi3.Parameter{contractID, username}, response: models.Contract{}},
+	{method: "PUT", path: "/api/contracts/{contractId}/complete", id: "completeContract", tag: "contracts", summary: "Завершение договора",
+		params: []*openapi3.Parameter{contractID, username}, response: models.Contract{}},
+
+	// Вложения
+	{method: "POST", path: "/api/tenders/{tenderId}/attachments", id: "uploadTenderAttachment", tag: "attachments", summary: "Загрузка вложения тендера",
+		params: []*openapi3.Parameter{tenderID, username}, upload: true, response: models.Attachment{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/attachments", id: "getTenderAttachments", tag: "attachments", summary: "Вложения тендера",
+		params: []*openapi3.Parameter{tenderID, username, fileVersion}, response: []models.Attachment{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/attachments/{attachmentId}", id: "downloadTenderAttachment", tag: "attachments", summary: "Скачивание вложения тендера",
+		params: []*openapi3.Parameter{tenderID, attachmentID, username}, content: "*/*"},
+	{method: "POST", path: "/api/bids/{bidId}/attachments", id: "uploadBidAttachment", tag: "attachments", summary: "Загрузка вложения предложения",
+		params: []*openapi3.Parameter{bidID, username}, upload: true, response: models.Attachment{}},
+	{method: "GET", path: "/api/bids/{bidId}/attachments", id: "getBidAttachments", tag: "attachments", summary: "Вложения предложения",
+		params: []*openapi3.Parameter{bidID, username, fileVersion}, response: []models.Attachment{}},
+	{method: "GET", path: "/api/bids/{bidId}/attachments/{attachmentId}", id: "downloadBidAttachment", tag: "attachments", summary: "Скачивание вложения предложения",
+		params: []*openapi3.Parameter{bidID, attachmentID, username}, content: "*/*"},
+
+	// Вопросы и разъяснения
+	{method: "POST", path: "/api/tenders/{tenderId}/questions", id: "createQuestion", tag: "questions", summary: "Вопрос по тендеру",
+		params: []*openapi3.Parameter{tenderID, username}, body: controllers.CreateQuestionRequest{}, response: models.TenderQuestion{}},
+	{method: "GET", path: "/api/tenders/{tenderId}/questions", id: "getQuestions", tag: "questions", summary: "Вопросы по тендеру",
+		params: []*openapi3.Parameter{tenderID, username, limit, offset}, response: []models.TenderQuestion{}},
+	{method: "PUT", path: "/api/tenders/{tenderId}/questions/{questionId}/answer", id: "answerQuestion", tag: "questions", summary: "Ответ на вопрос",
+		params: []*openapi3.Parameter{tenderID, questionID, username}, body: controllers.AnswerQuestionRequest{}, response: models.TenderQuestion{}},
+
+	// Предложения. Маршруты GET /api/bids/{id}/... обрабатываются одним обработчиком по последнему сегменту пути
+	{method: "POST", path: "/api/bids/new", id: "createBid", tag: "bids", summary: "Создание предложения",
+		body: controllers.CreateBidRequest{}, response: models.Bid{}},
+	{method: "GET", path: "/api/bids/my", id: "getUserBids", tag: "bids", summary: "Предложения пользователя",
+		params: []*openapi3.Parameter{limit, offset, username}, response: []models.Bid{}},
+	{method: "GET", path: "/api/bids/{tenderId}/list", id: "getTenderBids", tag: "bids",
+		summary: "Предложения по тендеру; для закрытого тендера до вскрытия только их число",
+		params:  []*openapi3.Parameter{tenderID, username, limit, offset},
+		oneOf:   []any{[]models.Bid{}, controllers.SealedBidsResponse{}}},
+	{method: "GET", path: "/api/bids/{bidId}/status", id: "getBidStatus", tag: "bids", summary: "Статус предложения",
+		params: []*openapi3.Parameter{bidID, optUsername}, response: models.BidStatus("")},
+	{method: "PUT", path: "/api/bids/{bidId}/status", id: "updateBidStatus", tag: "bids", summary: "Изменение статуса предложения",
+		params: []*openapi3.Parameter{bidID, username, required(query("status", "Новый статус", bidStatus))}, response: models.Bid{}},
+	{method: "PATCH", path: "/api/bids/{bidId}/edit", id: "editBid", tag: "bids", summary: "Редактирование предложения",
+		params: []*openapi3.Parameter{bidID, username}, body: controllers.UpdateBidRequest{}, response: models.Bid{}},
+	{method: "PUT", path: "/api/bids/{bidId}/rollback/{version}", id: "rollbackBid", tag: "bids", summary: "Откат предложения к версии",
+		params: []*openapi3.Parameter{bidID, version, username}, response: models.Bid{}},
+	{method: "PUT", path: "/api/bids/{bidId}/withdraw", id: "withdrawBid", tag: "bids", summary: "Отзыв предложения",
+		params: []*openapi3.Parameter{bidID, username}, body: controllers.WithdrawBidRequest{}, response: models.BidWithdrawal{}},
+	{method: "PUT", path: "/api/bids/{bidId}/resubmit", id: "resubmitBid", tag: "bids", summary: "Повторная подача отозванного предложения",
+		params: []*openapi3.Parameter{bidID, username}, response: models.Bid{}},
+	{method: "GET", path: "/api/bids/{bidId}/withdrawals", id: "getWithdrawals", tag: "bids", summary: "История отзывов предложения",
+		params: []*openapi3.Parameter{bidID, username}, response: []models.BidWithdrawal{}},
+
+	// Решения
+	{method: "PUT", path: "/api/bids/{bidId}/submit_decision", id: "submitDecision", tag: "decisions", summary: "Решение по предложению или его лоту",
+		params: []*openapi3.Parameter{bidID, username, lotID,
+			required(query("decision", "Решение", enumOf(models.Approved, models.Rejected)))},
+		response: models.Bid{}},
+
+	// Отзывы
+	{method: "PUT", path: "/api/bids/{bidId}/feedback", id: "submitFeedback", tag: "reviews", summary: "Отзыв об авторе предложения",
+		params: []*openapi3.Parameter{bidID, username}, body: controllers.SubmitReviewRequest{}, response: models.Bid{}},
+	{method: "GET", path: "/api/bids/{tenderId}/reviews", id: "getReviews", tag: "reviews",
+		summary: "Отзывы по тендеру или об авторе предложений",
+		params: []*openapi3.Parameter{tenderID, limit, offset,
+			required(query("requesterUsername", "Пользователь, выполняющий запрос", openapi3.NewStringSchema())),
+			query("authorUsername", "Автор предложений; обязателен при scope=author", openapi3.NewStringSchema()),
+			query("scope", "Отзывы по тендеру или все отзывы об авторе", enumOf("tender", "author")),
+			query("order", "Порядок по дате", enumOf("asc", "desc"))},
+		response: []controllers.ReviewResponse{}},
+	{method: "GET", path: "/api/reviews/my", id: "getMyReviews", tag: "reviews", summary: "Отзывы о пользователе",
+		params: []*openapi3.Parameter{username, limit, offset}, response: []controllers.ReviewResponse{}},
+	{method: "PATCH", path: "/api/reviews/{reviewId}", id: "updateReview", tag: "reviews", summary: "Изменение отзыва",
+		params: []*openapi3.Parameter{reviewID, username}, body: controllers.UpdateReviewRequest{}, response: models.Review{}},
+	{method: "DELETE", path: "/api/reviews/{reviewId}", id: "deleteReview", tag: "reviews", summary: "Удаление отзыва",
+		params: []*openapi3.Parameter{reviewID, username}, response: models.Review{}},
+	{method: "POST", path: "/api/reviews/{reviewId}/reply", id: "replyToReview", tag: "reviews", summary: "Ответ автора предложения на отзыв",
+		params: []*openapi3.Parameter{reviewID, username}, body: controllers.ReplyReviewRequest{}, response: controllers.ReviewResponse{}},
+
+	// Уведомления
+	{method: "GET", path: "/api/notifications", id: "getNotifications", tag: "notifications", summary: "Входящие уведомления",
+		params: []*openapi3.Parameter{username, limit, offset,
+			query("unread", "Только непрочитанные", openapi3.NewBoolSchema())},
+		response: []models.Notification{}},
+	{method: "GET", path: "/api/notifications/unread_count", id: "getUnreadCount", tag: "notifications", summary: "Число непрочитанных уведомлений",
+		params: []*openapi3.Parameter{username}, response: UnreadCount{}},
+	{method: "PUT", path: "/api/notifications/read_all", id: "markAllRead", tag: "notifications", summary: "Отметить все уведомления прочитанными",
+		params: []*openapi3.Parameter{username}, response: UpdatedCount{}},
+	{method: "PUT", path: "/api/notifications/{notificationId}/read", id: "markRead", tag: "notifications", summary: "Отметить уведомление прочитанным",
+		params: []*openapi3.Parameter{notificationID, username}, response: models.Notification{}},
+	{method: "GET", path: "/api/notifications/preferences", id: "getPreferences", tag: "notifications", summary: "Настройки уведомлений",
+		params: []*openapi3.Parameter{username}, response: controllers.NotificationPreferencesResponse{}},
+	{method: "PUT", path: "/api/notifications/preferences", id: "updatePreferences", tag: "notifications", summary: "Изменение настроек уведомлений",
+		params: []*openapi3.Parameter{username}, body: controllers.UpdateNotificationPreferencesRequest{},
+		response: controllers.NotificationPreferencesResponse{}},
+
+	// GraphQL: только чтение, правила доступа совпадают с REST API
+	{method: "POST", path: "/api/graphql", id: "graphql", tag: "graphql", summary: "Запрос GraphQL по тендерам, предложениям, решениям и отзывам",
+		params: []*openapi3.Parameter{optUsername}, body: graph.QueryRequest{}, response: GraphQLResponse{}},
+
+	// Поток событий: каждое сообщение text/event-stream содержит StreamEvent в формате JSON
+	{method: "GET", path: "/api/stream", id: "stream", tag: "events", summary: "Поток событий (Server-Sent Events) в формате StreamEvent",
+		params: []*openapi3.Parameter{username,
+			query("lastEventId", "Последнее полученное событие, если нельзя передать заголовок Last-Event-ID", openapi3.NewInt64Schema()),
+			openapi3.NewHeaderParameter("Last-Event-ID").WithDescription("Последнее полученное событие").WithSchema(openapi3.NewInt64Schema())},
+		content: "text/event-stream"},
+}
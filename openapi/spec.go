@@ -0,0 +1,288 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/google/uuid"
+	"myapp/controllers"
+	"myapp/models"
+)
+
+const Version = "1.0.0"
+
+// operation - описание одного маршрута API. Схемы тел запроса и ответа выводятся из типов Go,
+// которые используют контроллеры, поэтому спецификация не расходится с моделями
+type operation struct {
+	method  string
+	path    string
+	id      string
+	tag     string
+	summary string
+	params  []*openapi3.Parameter
+	// body - значение типа тела запроса в формате JSON; для загрузки файлов используется upload
+	body   any
+	upload bool
+	// response - значение типа успешного ответа в формате JSON; для остальных форматов задаётся content
+	response any
+	content  string
+	// oneOf - альтернативные типы ответа, если содержимое зависит от состояния ресурса
+	oneOf []any
+}
+
+var (
+	buildOnce sync.Once
+	doc       *openapi3.T
+	docJSON   []byte
+	buildErr  error
+)
+
+// Spec возвращает спецификацию OpenAPI 3 для всех маршрутов сервиса. Документ строится один раз
+// и проверяется; ссылки на схемы в нём разрешены, поэтому его можно сразу использовать для проверки запросов
+func Spec() (*openapi3.T, error) {
+	buildOnce.Do(func() {
+		doc, docJSON, buildErr = build()
+	})
+	return doc, buildErr
+}
+
+// JSON возвращает спецификацию в том виде, в котором её отдаёт /api/openapi.json
+func JSON() ([]byte, error) {
+	if _, err := Spec(); err != nil {
+		return nil, err
+	}
+	return docJSON, nil
+}
+
+func build() (*openapi3.T, []byte, error) {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Tender Service API",
+			Description: "Тендеры, предложения, решения, отзывы и договоры. Пользователь определяется параметром username",
+			Version:     Version,
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"ErrorResponse": openapi3.NewSchemaRef("", openapi3.NewObjectSchema().
+					WithProperty("reason", openapi3.NewStringSchema())),
+			},
+		},
+	}
+
+	gen := openapi3gen.NewGenerator(
+		openapi3gen.SchemaCustomizer(customize),
+		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
+			ExportComponentSchemas: true,
+			ExportTopLevelSchema:   true,
+		}),
+	)
+	schema := func(value any) (*openapi3.SchemaRef, error) {
+		return gen.NewSchemaRefForValue(value, spec.Components.Schemas)
+	}
+
+	for _, op := range operations {
+		o, err := op.build(schema)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %w", op.method, op.path, err)
+		}
+
+		item := spec.Paths.Value(op.path)
+		if item == nil {
+			item = &openapi3.PathItem{}
+			spec.Paths.Set(op.path, item)
+		}
+		if item.GetOperation(op.method) != nil {
+			return nil, nil, fmt.Errorf("%s %s: duplicate operation", op.method, op.path)
+		}
+		item.SetOperation(op.method, o)
+	}
+
+	// События потока описываются отдельной схемой: сам ответ имеет формат text/event-stream
+	if _, err := schema(controllers.StreamEvent{}); err != nil {
+		return nil, nil, err
+	}
+
+	// Поля time.Time генератор выносит в компонент Time, но не сохраняет его схему, так как у неё нет свойств.
+	// Признак nullable у ссылок теряется, поэтому необязательные даты допускают null в самом компоненте
+	spec.Components.Schemas["Time"] = openapi3.NewSchemaRef("", openapi3.NewDateTimeSchema().WithNullable())
+
+	// Генератор оставляет ссылки на компоненты без значений. После сериализации и загрузки
+	// документа все ссылки разрешены, как если бы спецификация была прочитана из файла
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := loaded.Validate(context.Background()); err != nil {
+		return nil, nil, err
+	}
+
+	return loaded, data, nil
+}
+
+func (op operation) build(schema func(any) (*openapi3.SchemaRef, error)) (*openapi3.Operation, error) {
+	o := openapi3.NewOperation()
+	o.OperationID = op.id
+	o.Summary = op.summary
+	o.Tags = []string{op.tag}
+
+	for _, p := range op.params {
+		o.AddParameter(p)
+	}
+
+	if op.body != nil {
+		ref, err := schema(op.body)
+		if err != nil {
+			return nil, err
+		}
+		o.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(ref)}
+	}
+	if op.upload {
+		form := openapi3.NewObjectSchema().
+			WithProperty("file", openapi3.NewStringSchema().WithFormat("binary")).
+			WithRequired([]string{"file"})
+		o.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).
+			WithContent(openapi3.NewContentWithSchema(form, []string{"multipart/form-data"}))}
+	}
+
+	ok := openapi3.NewResponse().WithDescription(http.StatusText(http.StatusOK))
+	switch {
+	case op.response != nil:
+		ref, err := schema(op.response)
+		if err != nil {
+			return nil, err
+		}
+		ok.WithJSONSchemaRef(ref)
+	case op.oneOf != nil:
+		alternatives := openapi3.NewSchema()
+		for _, v := range op.oneOf {
+			ref, err := schema(v)
+			if err != nil {
+				return nil, err
+			}
+			alternatives.OneOf = append(alternatives.OneOf, ref)
+		}
+		ok.WithJSONSchema(alternatives)
+	case op.content != "":
+		body := openapi3.NewStringSchema()
+		if op.content != "text/plain" && op.content != "text/html" && op.content != "text/event-stream" {
+			body.WithFormat("binary")
+		}
+		ok.WithContent(openapi3.NewContentWithSchema(body, []string{op.content}))
+	}
+
+	errResponse := openapi3.NewResponse().
+		WithDescription("Ошибка; причина передаётся в поле reason").
+		WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/ErrorResponse", nil))
+
+	o.Responses = openapi3.NewResponses(
+		openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: ok}),
+		openapi3.WithName("default", errResponse),
+	)
+
+	return o, nil
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+
+	// Допустимые значения перечислений из models
+	enums = map[reflect.Type][]any{
+		reflect.TypeOf(models.ServiceType("")):      {models.Construction, models.Delivery, models.Manufacture},
+		reflect.TypeOf(models.Status("")):           {models.Created, models.Published, models.Closed},
+		reflect.TypeOf(models.Visibility("")):       {models.VisibilityPublic, models.VisibilityInviteOnly},
+		reflect.TypeOf(models.AuthorType("")):       {models.AuthorOrganization, models.AuthorUser},
+		reflect.TypeOf(models.BidStatus("")):        {models.BidCreated, models.BidPublished, models.BidCanceled, models.BidWithdrawn},
+		reflect.TypeOf(models.DecisionType("")):     {models.Approved, models.Rejected},
+		reflect.TypeOf(models.OrganizationType("")): {models.IE, models.LLC, models.JSC},
+		reflect.TypeOf(models.RoundStatus("")):      {models.RoundOpen, models.RoundClosed},
+		reflect.TypeOf(models.LotStatus("")):        {models.LotOpen, models.LotAwarded},
+		reflect.TypeOf(models.BidLotStatus("")):     {models.BidLotPending, models.BidLotApproved, models.BidLotRejected},
+		reflect.TypeOf(models.OpeningReason("")):    {models.OpeningManual, models.OpeningDeadline},
+		reflect.TypeOf(models.EventType("")): {
+			models.EventTenderStatus, models.EventBidPublished, models.EventDecision, models.EventFeedback,
+			models.EventAuctionRound, models.EventAuctionPrice, models.EventBidWithdrawn,
+		},
+		reflect.TypeOf(models.NotificationType("")): {
+			models.NotificationBidDecision, models.NotificationBidFeedback, models.NotificationTenderClosed,
+			models.NotificationNewBid, models.NotificationReviewReply,
+		},
+		reflect.TypeOf(models.ContractStatus("")): {
+			models.ContractDraft, models.ContractSignedByBuyer, models.ContractSignedBySupplier,
+			models.ContractActive, models.ContractCompleted,
+		},
+	}
+)
+
+// customize дополняет схемы, выведенные из типов Go: идентификаторы описываются как строки uuid,
+// перечисления - списком значений, а правила из тегов binding - обязательными полями и ограничениями
+func customize(_ string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t == uuidType {
+		schema.Type = &openapi3.Types{openapi3.TypeString}
+		schema.Format = "uuid"
+	}
+	if values, ok := enums[t]; ok {
+		schema.Enum = values
+	}
+
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+				if rule == "required" {
+					schema.Required = append(schema.Required, name)
+				}
+			}
+		}
+	}
+
+	for _, rule := range strings.Split(tag.Get("binding"), ",") {
+		key, value, found := strings.Cut(rule, "=")
+		if !found {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+
+		isString := schema.Type.Is(openapi3.TypeString)
+		switch key {
+		case "max":
+			if isString {
+				schema.MaxLength = openapi3.Uint64Ptr(uint64(n))
+			} else {
+				schema.Max = openapi3.Float64Ptr(n)
+			}
+		case "min":
+			if isString {
+				schema.MinLength = uint64(n)
+			} else {
+				schema.Min = openapi3.Float64Ptr(n)
+			}
+		case "gt":
+			schema.Min = openapi3.Float64Ptr(n)
+			schema.ExclusiveMin = true
+		}
+	}
+
+	return nil
+}
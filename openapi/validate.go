@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"bytes"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+// Ответы больше этого размера не проверяются, чтобы не держать в памяти вложения и поток событий
+const maxValidatedResponse = 1 << 20
+
+// ValidationMiddleware проверяет запросы и ответы по спецификации. Запрос, который ей не соответствует,
+// отклоняется с кодом 400 до вызова контроллера; несоответствие ответа только записывается в журнал.
+// Предназначено для режима разработки: проверка добавляет разбор каждого тела запроса и ответа
+func ValidationMiddleware() (gin.HandlerFunc, error) {
+	spec, err := Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			// Неизвестные маршруты обрабатывает gin
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		options := &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			// Файлы проверяет контроллер: декодирование multipart потребовало бы прочитать файл в память
+			ExcludeRequestBody: isMultipart(c.Request),
+		}
+		options.WithCustomSchemaErrorFunc(schemaError)
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+
+		if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"reason": "Request does not match the API specification: " + err.Error()})
+			slog.WarnContext(ctx, "Request does not match the API specification", "operation", route.Operation.OperationID, "error", err)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		validateResponse(c, input, route, recorder)
+	}, nil
+}
+
+func validateResponse(c *gin.Context, input *openapi3filter.RequestValidationInput, route *routers.Route, recorder *responseRecorder) {
+	mediaType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	if mediaType != "application/json" || recorder.truncated {
+		return
+	}
+
+	response := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 recorder.Status(),
+		Header:                 recorder.Header(),
+		Options:                &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true},
+	}
+	response.Options.WithCustomSchemaErrorFunc(schemaError)
+	response.SetBodyBytes(recorder.body.Bytes())
+
+	ctx := c.Request.Context()
+	if err := openapi3filter.ValidateResponse(ctx, response); err != nil {
+		slog.WarnContext(ctx, "Response does not match the API specification",
+			"operation", route.Operation.OperationID, "status", recorder.Status(), "error", err)
+	}
+}
+
+// schemaError сокращает сообщение об ошибке до поля и причины, без текста схемы и значения
+func schemaError(err *openapi3.SchemaError) string {
+	if pointer := err.JSONPointer(); len(pointer) > 0 {
+		return "/" + strings.Join(pointer, "/") + ": " + err.Reason
+	}
+	return err.Reason
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "multipart/form-data"
+}
+
+// responseRecorder передаёт ответ клиенту без изменений и сохраняет копию тела для проверки
+type responseRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.record([]byte(s))
+	return r.ResponseWriter.WriteString(s)
+}
+
+func (r *responseRecorder) record(data []byte) {
+	if r.truncated {
+		return
+	}
+	if r.body.Len()+len(data) > maxValidatedResponse {
+		r.truncated = true
+		r.body.Reset()
+		return
+	}
+	r.body.Write(data)
+}
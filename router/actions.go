@@ -0,0 +1,94 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bidActions - действия маршрута GET /api/bids/:id/*action в порядке сопоставления. gin не позволяет
+// объявить /api/bids/:tenderId/list рядом с /api/bids/my, поэтому действия разбирает один обработчик
+var bidActions = []string{
+	"/list",
+	"/status",
+	"/reviews",
+	"/withdrawals",
+	"/attachments",
+	"/attachments/:attachmentId",
+}
+
+// wildcardActions - действия маршрутов с параметром *action по методу и пути маршрута
+var wildcardActions = map[string][]string{
+	"GET /api/bids/:id/*action": bidActions,
+}
+
+// Routes возвращает маршруты r, заменяя маршрут с параметром *action маршрутами его действий,
+// чтобы каждое действие сверялось со спецификацией отдельно
+func Routes(r *gin.Engine) gin.RoutesInfo {
+	var routes gin.RoutesInfo
+	for _, route := range r.Routes() {
+		actions, ok := wildcardActions[route.Method+" "+route.Path]
+		if !ok {
+			routes = append(routes, route)
+			continue
+		}
+
+		prefix := strings.TrimSuffix(route.Path, "/*action")
+		for _, action := range actions {
+			expanded := route
+			expanded.Path = prefix + action
+			routes = append(routes, expanded)
+		}
+	}
+	return routes
+}
+
+// dispatch возвращает обработчик маршрута с параметром *action, который передаёт запрос обработчику
+// первого подходящего действия. Параметры действия вида :name добавляются к параметрам запроса.
+// Каждому действию из actions должен соответствовать обработчик, иначе маршруты разошлись бы со спецификацией
+func dispatch(actions []string, handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	if len(handlers) != len(actions) {
+		panic(fmt.Sprintf("router: %d action handlers for %d actions", len(handlers), len(actions)))
+	}
+	for _, action := range actions {
+		if handlers[action] == nil {
+			panic(fmt.Sprintf("router: action %s has no handler", action))
+		}
+	}
+
+	return func(c *gin.Context) {
+		segments := strings.Split(c.Param("action"), "/")
+		for _, action := range actions {
+			if params, ok := matchAction(action, segments); ok {
+				c.Params = append(c.Params, params...)
+				handlers[action](c)
+				return
+			}
+		}
+		c.JSON(400, gin.H{"error": "Invalid action"})
+	}
+}
+
+// matchAction сопоставляет сегменты запрошенного действия с шаблоном действия и возвращает значения его параметров
+func matchAction(action string, segments []string) (gin.Params, bool) {
+	pattern := strings.Split(action, "/")
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	var params gin.Params
+	for i, s := range pattern {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params = append(params, gin.Param{Key: name, Value: segments[i]})
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
@@ -1,22 +1,79 @@
 package router
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"myapp/config"
 	"myapp/controllers"
+	"myapp/documents"
+	"myapp/events"
+	"myapp/graph"
 	"myapp/handlers"
+	"myapp/health"
+	"myapp/logging"
+	"myapp/metrics"
+	"myapp/notify"
+	"myapp/openapi"
+	"myapp/storage"
+	"myapp/tracing"
 )
 
-func SetupRouter(db *gorm.DB) *gin.Engine {
-	router := gin.Default()
-	reviewController := controllers.ReviewController{DB: db}
-	decisionController := controllers.DecisionController{DB: db}
-	tenderController := controllers.TenderController{DB: db}
-	bidController := controllers.BidController{DB: db}
+func SetupRouter(cfg *config.Config, db *gorm.DB, broker *events.Broker, notifications *notify.Service, store storage.BlobStore, contracts *documents.ContractRenderer, checker *health.Checker) *gin.Engine {
+	router := gin.New()
+
+	// Проверки для оркестратора регистрируются до middleware, чтобы частые запросы проб
+	// не попадали в журнал, метрики и трассировки
+	router.GET("/healthz", handlers.LivenessHandler)
+	router.GET("/readyz", handlers.ReadinessHandler(checker))
+
+	// Вместо стандартного текстового журнала gin используется структурированный журнал с идентификатором запроса
+	router.Use(logging.RequestIDMiddleware(), logging.AccessLogMiddleware(), logging.RecoveryMiddleware())
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		router.Use(tracing.Middleware())
+	}
+	if cfg.Metrics.Enabled {
+		router.Use(metrics.Middleware())
+	}
+
+	// В режиме разработки запросы и ответы проверяются по спецификации OpenAPI
+	dev := cfg.HTTP.GinMode == gin.DebugMode
+	if dev {
+		validation, err := openapi.ValidationMiddleware()
+		if err != nil {
+			slog.Error("Failed to build API specification, validation is disabled", "error", err)
+		} else {
+			router.Use(validation)
+		}
+	}
+
+	reviewController := controllers.ReviewController{DB: db, Notifications: notifications, Pagination: cfg.Pagination}
+	decisionController := controllers.DecisionController{DB: db, Notifications: notifications, Quorum: cfg.Quorum}
+	tenderController := controllers.TenderController{DB: db, Notifications: notifications, Pagination: cfg.Pagination}
+	bidController := controllers.BidController{DB: db, Notifications: notifications, Pagination: cfg.Pagination}
+	streamController := controllers.StreamController{DB: db, Broker: broker}
+	notificationController := controllers.NotificationController{DB: db, Pagination: cfg.Pagination}
+	questionController := controllers.QuestionController{DB: db, Pagination: cfg.Pagination}
+	auctionController := controllers.AuctionController{DB: db}
+	invitationController := controllers.InvitationController{DB: db}
+	withdrawalController := controllers.WithdrawalController{DB: db, Notifications: notifications}
+	contractController := controllers.ContractController{DB: db, Renderer: contracts, Quorum: cfg.Quorum}
+	graphHandler := graph.NewHandler(db, cfg.Pagination)
+	attachmentController := controllers.AttachmentController{
+		DB:           db,
+		Store:        store,
+		MaxSize:      cfg.Storage.MaxSize,
+		AllowedTypes: cfg.Storage.AllowedTypes,
+	}
 
 	// Маршрут для проверки доступности сервера
 	router.GET("/api/ping", handlers.PingHandler)
 
+	// Спецификация API и Swagger UI
+	router.GET("/api/openapi.json", openapi.Handler)
+	router.GET("/api/docs", openapi.DocsHandler)
+
 	// Маршруты для тендеров
 	router.GET("/api/tenders", tenderController.GetTenders)
 	router.POST("/api/tenders/new", tenderController.CreateTender)
@@ -25,32 +82,80 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 	router.PUT("/api/tenders/:tenderId/status", tenderController.UpdateTenderStatus)
 	router.PATCH("/api/tenders/:tenderId/edit", tenderController.EditTender)
 	router.PUT("/api/tenders/:tenderId/rollback/:version", tenderController.RollbackTender)
+	router.PUT("/api/tenders/:tenderId/open", tenderController.OpenTenderBids)
+	router.POST("/api/tenders/:tenderId/lots", tenderController.CreateLot)
+	router.GET("/api/tenders/:tenderId/lots", tenderController.GetLots)
+	router.POST("/api/tenders/:tenderId/rounds", auctionController.OpenRound)
+	router.GET("/api/tenders/:tenderId/rounds", auctionController.GetRounds)
+	router.POST("/api/tenders/:tenderId/invitations", invitationController.CreateInvitation)
+	router.GET("/api/tenders/:tenderId/invitations", invitationController.GetInvitations)
+	router.DELETE("/api/tenders/:tenderId/invitations/:invitationId", invitationController.DeleteInvitation)
+	router.POST("/api/tenders/:tenderId/contracts", contractController.CreateContract)
+	router.GET("/api/tenders/:tenderId/contracts", contractController.GetTenderContracts)
+	router.POST("/api/tenders/:tenderId/attachments", attachmentController.UploadTenderAttachment)
+	router.GET("/api/tenders/:tenderId/attachments", attachmentController.GetTenderAttachments)
+	router.GET("/api/tenders/:tenderId/attachments/:attachmentId", attachmentController.DownloadTenderAttachment)
+
+	// Маршруты для вопросов и разъяснений по тендерам
+	router.POST("/api/tenders/:tenderId/questions", questionController.CreateQuestion)
+	router.GET("/api/tenders/:tenderId/questions", questionController.GetQuestions)
+	router.PUT("/api/tenders/:tenderId/questions/:questionId/answer", questionController.AnswerQuestion)
 
 	// Маршруты для предложений
 	router.POST("/api/bids/new", bidController.CreateBid)
 	router.GET("/api/bids/my", bidController.GetUserBids)
-	router.GET("/api/bids/:id/*action", func(c *gin.Context) {
-		action := c.Param("action")
-
-		if action == "/list" {
-			bidController.GetTenderBids(c)
-		} else if action == "/status" {
-			bidController.GetBidStatus(c)
-		} else if action == "/reviews" {
-			reviewController.GetReviews(c)
-		} else {
-			c.JSON(400, gin.H{"error": "Invalid action"})
-		}
-	})
+	router.GET("/api/bids/:id/*action", dispatch(bidActions, map[string]gin.HandlerFunc{
+		"/list":                      bidController.GetTenderBids,
+		"/status":                    bidController.GetBidStatus,
+		"/reviews":                   reviewController.GetReviews,
+		"/withdrawals":               withdrawalController.GetWithdrawals,
+		"/attachments":               attachmentController.GetBidAttachments,
+		"/attachments/:attachmentId": attachmentController.DownloadBidAttachment,
+	}))
 	router.PUT("/api/bids/:bidID/status", bidController.UpdateBidStatus)
 	router.PATCH("/api/bids/:bidID/edit", bidController.EditBid)
+	router.PUT("/api/bids/:bidID/price", auctionController.LowerBidPrice)
+	router.PUT("/api/bids/:bidID/withdraw", withdrawalController.WithdrawBid)
+	router.PUT("/api/bids/:bidID/resubmit", withdrawalController.ResubmitBid)
 	router.PUT("/api/bids/:bidID/rollback/:version", bidController.RollbackBid)
+	router.POST("/api/bids/:bidID/attachments", attachmentController.UploadBidAttachment)
 
 	// Маршруты для решений по предложениям
 	router.PUT("/api/bids/:bidID/submit_decision", decisionController.SubmitDecision)
 
 	// Маршруты для отзывов
 	router.PUT("/api/bids/:bidID/feedback", reviewController.SubmitFeedback)
+	router.GET("/api/reviews/my", reviewController.GetMyReviews)
+	router.PATCH("/api/reviews/:reviewId", reviewController.UpdateReview)
+	router.POST("/api/reviews/:reviewId/reply", reviewController.ReplyToReview)
+	router.DELETE("/api/reviews/:reviewId", reviewController.DeleteReview)
+
+	// Маршруты для договоров
+	router.GET("/api/contracts/:contractId", contractController.GetContract)
+	router.GET("/api/contracts/:contractId/pdf", contractController.DownloadContractPDF)
+	router.PUT("/api/contracts/:contractId/sign/buyer", contractController.SignAsBuyer)
+	router.PUT("/api/contracts/:contractId/sign/supplier", contractController.SignAsSupplier)
+	router.PUT("/api/contracts/:contractId/complete", contractController.CompleteContract)
+
+	// Маршруты для уведомлений
+	router.GET("/api/notifications", notificationController.GetNotifications)
+	router.GET("/api/notifications/unread_count", notificationController.GetUnreadCount)
+	router.PUT("/api/notifications/read_all", notificationController.MarkAllRead)
+	router.PUT("/api/notifications/:notificationId/read", notificationController.MarkRead)
+	router.GET("/api/notifications/preferences", notificationController.GetPreferences)
+	router.PUT("/api/notifications/preferences", notificationController.UpdatePreferences)
+
+	// GraphQL API для чтения
+	router.POST("/api/graphql", graphHandler.Query)
+
+	// Поток событий (Server-Sent Events)
+	router.GET("/api/stream", streamController.Stream)
+
+	if dev {
+		if err := openapi.Check(Routes(router)); err != nil {
+			slog.Warn("Routes do not match the API specification", "error", err)
+		}
+	}
 
 	return router
 }
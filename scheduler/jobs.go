@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"myapp/events"
+	"myapp/models"
+)
+
+// OpenSealedTenders фиксирует вскрытие запечатанных предложений у тендеров, срок подачи которых истёк.
+// Временем вскрытия считается сам срок подачи
+func OpenSealedTenders(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return db.WithContext(ctx).Exec(`
+            INSERT INTO tender_openings (id, tender_id, reason, created_at)
+            SELECT uuid_generate_v4(), t.id, ?, t.deadline
+            FROM tenders t
+            WHERE t.sealed AND t.deadline <= NOW()
+            ON CONFLICT (tender_id) DO NOTHING
+        `, models.OpeningDeadline).Error
+	}
+}
+
+// CloseAuctionRounds закрывает раунды обратного аукциона, время которых истекло, и оповещает участников
+func CloseAuctionRounds(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var rounds []models.AuctionRound
+
+		tx := db.WithContext(ctx)
+		if err := tx.Preload("Tender").Where("status = ? AND ends_at <= NOW()", models.RoundOpen).Find(&rounds).Error; err != nil {
+			return err
+		}
+
+		for _, round := range rounds {
+			// Условие на статус защищает от повторного закрытия при параллельном запуске
+			result := tx.Model(&models.AuctionRound{}).Where("id = ? AND status = ?", round.ID, models.RoundOpen).
+				Updates(map[string]any{"status": models.RoundClosed, "closed_at": gorm.Expr("NOW()")})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			round.Status = models.RoundClosed
+			if err := events.AuctionRoundChanged(tx, round, round.Tender); err != nil {
+				slog.ErrorContext(ctx, "Failed to publish auction round event", "round_id", round.ID, "error", err)
+			}
+		}
+
+		return nil
+	}
+}
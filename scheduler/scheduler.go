@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"myapp/health"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+	beat     *health.Heartbeat
+}
+
+// Scheduler периодически выполняет фоновые задачи сервера
+type Scheduler struct {
+	jobs []job
+	wg   sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Add(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run, beat: &health.Heartbeat{}})
+}
+
+// Start запускает задачи до отмены контекста. Каждая задача выполняется сразу и затем с заданным интервалом
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j job) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				if err := j.run(ctx); err != nil && ctx.Err() == nil {
+					slog.Error("Scheduled job failed", "job", j.name, "error", err)
+				}
+				j.beat.Beat()
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(j)
+	}
+}
+
+// Check сообщает об ошибке, если какая-либо задача не выполнялась дольше двух своих интервалов.
+// Ошибка самой задачи не считается зависанием: она записывается в журнал и повторяется на следующем шаге
+func (s *Scheduler) Check(context.Context) error {
+	var stalled []string
+	for _, j := range s.jobs {
+		if since, ok := j.beat.Since(); !ok || since > 2*j.interval {
+			stalled = append(stalled, j.name)
+		}
+	}
+
+	if len(stalled) > 0 {
+		return fmt.Errorf("jobs not running: %s", strings.Join(stalled, ", "))
+	}
+	return nil
+}
+
+// Wait ожидает завершения выполняющихся задач после отмены контекста
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
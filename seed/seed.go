@@ -0,0 +1,453 @@
+package seed
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"myapp/admin"
+	"myapp/config"
+	"myapp/models"
+	"myapp/workflow"
+)
+
+//go:embed demo.yaml
+var demoData []byte
+
+// namespace - пространство имён для идентификаторов фикстур. Идентификатор выводится из естественного ключа записи,
+// поэтому одни и те же фикстуры дают одинаковые идентификаторы на любой базе данных
+var namespace = uuid.MustParse("6f1c2b4e-3d8a-4c55-9b7e-2a0f5e8d1c34")
+
+// Fixture - набор данных для загрузки. Записи ссылаются друг на друга по естественным ключам:
+// организации - по имени, сотрудники - по username, тендеры - по организации и имени (имена тендеров разных
+// организаций могут совпадать), предложения - по имени внутри тендера
+type Fixture struct {
+	Organizations []Organization `yaml:"organizations" json:"organizations"`
+	Employees     []Employee     `yaml:"employees" json:"employees"`
+	Responsibles  []Responsible  `yaml:"responsibles" json:"responsibles"`
+	Tenders       []Tender       `yaml:"tenders" json:"tenders"`
+	Bids          []Bid          `yaml:"bids" json:"bids"`
+	Decisions     []Decision     `yaml:"decisions" json:"decisions"`
+}
+
+type Organization struct {
+	Name        string                  `yaml:"name" json:"name"`
+	Description string                  `yaml:"description" json:"description"`
+	Type        models.OrganizationType `yaml:"type" json:"type"`
+}
+
+type Employee struct {
+	Username  string `yaml:"username" json:"username"`
+	FirstName string `yaml:"firstName" json:"firstName"`
+	LastName  string `yaml:"lastName" json:"lastName"`
+	Email     string `yaml:"email" json:"email"`
+}
+
+type Responsible struct {
+	Organization string `yaml:"organization" json:"organization"`
+	Username     string `yaml:"username" json:"username"`
+}
+
+type Tender struct {
+	Name         string             `yaml:"name" json:"name"`
+	Organization string             `yaml:"organization" json:"organization"`
+	Description  string             `yaml:"description" json:"description"`
+	ServiceType  models.ServiceType `yaml:"serviceType" json:"serviceType"`
+	Status       models.Status      `yaml:"status" json:"status"`
+	Visibility   models.Visibility  `yaml:"visibility" json:"visibility"`
+	Sealed       bool               `yaml:"sealed" json:"sealed"`
+	AuctionMode  bool               `yaml:"auctionMode" json:"auctionMode"`
+	Deadline     *time.Time         `yaml:"deadline" json:"deadline"`
+}
+
+type Bid struct {
+	Name               string            `yaml:"name" json:"name"`
+	Tender             string            `yaml:"tender" json:"tender"`
+	TenderOrganization string            `yaml:"tenderOrganization" json:"tenderOrganization"`
+	Author             string            `yaml:"author" json:"author"`
+	AuthorType         models.AuthorType `yaml:"authorType" json:"authorType"`
+	Organization       string            `yaml:"organization" json:"organization"`
+	Description        string            `yaml:"description" json:"description"`
+	Status             models.BidStatus  `yaml:"status" json:"status"`
+	Price              *float64          `yaml:"price" json:"price"`
+}
+
+type Decision struct {
+	Tender             string              `yaml:"tender" json:"tender"`
+	TenderOrganization string              `yaml:"tenderOrganization" json:"tenderOrganization"`
+	Bid                string              `yaml:"bid" json:"bid"`
+	Author             string              `yaml:"author" json:"author"`
+	Decision           models.DecisionType `yaml:"decision" json:"decision"`
+}
+
+// Result - сколько записей каждого вида создано и сколько пропущено как уже существующие
+type Result struct {
+	Created map[string]int `json:"created"`
+	Skipped map[string]int `json:"skipped"`
+}
+
+func (r Result) String() string {
+	var parts []string
+	for _, kind := range []string{"organizations", "employees", "responsibles", "tenders", "bids", "decisions"} {
+		parts = append(parts, fmt.Sprintf("%s: %d created, %d skipped", kind, r.Created[kind], r.Skipped[kind]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Demo возвращает встроенный демонстрационный набор данных
+func Demo() (Fixture, error) {
+	var f Fixture
+	if err := yaml.Unmarshal(demoData, &f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// ParseFile читает фикстуры из файла YAML (.yaml, .yml) или JSON (.json)
+func ParseFile(path string) (Fixture, error) {
+	var f Fixture
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&f)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(strings.NewReader(string(data)))
+		dec.KnownFields(true)
+		err = dec.Decode(&f)
+	default:
+		return f, fmt.Errorf("%s: unsupported fixture format, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return f, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Load загружает фикстуры в одной транзакции. Уже существующие записи не изменяются и не дублируются,
+// поэтому повторная загрузка тех же фикстур безопасна. При любой ошибке база данных остаётся без изменений.
+// Решения проходят тот же переход, что и через API, поэтому quorum определяет, когда тендер закрывается
+func Load(ctx context.Context, db *gorm.DB, f Fixture, quorum config.Quorum) (Result, error) {
+	result := Result{Created: map[string]int{}, Skipped: map[string]int{}}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		l := loader{
+			tx:      tx,
+			svc:     admin.Service{DB: tx},
+			quorum:  quorum,
+			result:  result,
+			tenders: map[string]models.Tender{},
+			bids:    map[string]models.Bid{},
+		}
+		return l.load(ctx, f)
+	})
+
+	return result, err
+}
+
+type loader struct {
+	tx      *gorm.DB
+	svc     admin.Service
+	quorum  config.Quorum
+	result  Result
+	tenders map[string]models.Tender
+	bids    map[string]models.Bid
+}
+
+// count учитывает результат создания записи: ErrExists означает, что запись уже загружена ранее
+func (l loader) count(kind string, err error) error {
+	switch {
+	case err == nil:
+		l.result.Created[kind]++
+	case errors.Is(err, admin.ErrExists):
+		l.result.Skipped[kind]++
+	default:
+		return err
+	}
+	return nil
+}
+
+func (l loader) load(ctx context.Context, f Fixture) error {
+	for _, o := range f.Organizations {
+		_, err := l.svc.CreateOrganization(ctx, admin.OrganizationInput{
+			ID:          fixtureID("organization", o.Name),
+			Name:        o.Name,
+			Description: o.Description,
+			Type:        o.Type,
+		})
+		if err := l.count("organizations", err); err != nil {
+			return fmt.Errorf("organization %q: %w", o.Name, err)
+		}
+	}
+
+	for _, e := range f.Employees {
+		_, err := l.svc.CreateEmployee(ctx, admin.EmployeeInput{
+			ID:        fixtureID("employee", e.Username),
+			Username:  e.Username,
+			FirstName: e.FirstName,
+			LastName:  e.LastName,
+			Email:     e.Email,
+		})
+		if err := l.count("employees", err); err != nil {
+			return fmt.Errorf("employee %q: %w", e.Username, err)
+		}
+	}
+
+	for _, r := range f.Responsibles {
+		_, err := l.svc.AddResponsible(ctx, r.Organization, r.Username)
+		if err := l.count("responsibles", err); err != nil {
+			return fmt.Errorf("responsible %q for %q: %w", r.Username, r.Organization, err)
+		}
+	}
+
+	for _, t := range f.Tenders {
+		if err := l.count("tenders", l.tender(ctx, t)); err != nil {
+			return fmt.Errorf("tender %q: %w", t.Name, err)
+		}
+	}
+
+	for _, b := range f.Bids {
+		if err := l.count("bids", l.bid(ctx, b)); err != nil {
+			return fmt.Errorf("bid %q on tender %q of %q: %w", b.Name, b.Tender, b.TenderOrganization, err)
+		}
+	}
+
+	for _, d := range f.Decisions {
+		if err := l.count("decisions", l.decision(ctx, d)); err != nil {
+			return fmt.Errorf("decision by %q on bid %q: %w", d.Author, d.Bid, err)
+		}
+	}
+
+	return nil
+}
+
+func (l loader) tender(ctx context.Context, t Tender) error {
+	org, err := l.svc.FindOrganization(ctx, t.Organization)
+	if err != nil {
+		return err
+	}
+
+	if t.Name == "" || len(t.Name) > 100 || t.Description == "" || len(t.Description) > 500 {
+		return fmt.Errorf("%w: name must be 1-100 and description 1-500 characters", admin.ErrInvalid)
+	}
+	switch t.ServiceType {
+	case models.Construction, models.Delivery, models.Manufacture:
+	default:
+		return fmt.Errorf("%w: unknown service type %q", admin.ErrInvalid, t.ServiceType)
+	}
+	if t.Status == "" {
+		t.Status = models.Created
+	}
+	switch t.Status {
+	case models.Created, models.Published, models.Closed:
+	default:
+		return fmt.Errorf("%w: unknown status %q", admin.ErrInvalid, t.Status)
+	}
+	if t.Visibility == "" {
+		t.Visibility = models.VisibilityPublic
+	}
+	if t.Visibility != models.VisibilityPublic && t.Visibility != models.VisibilityInviteOnly {
+		return fmt.Errorf("%w: unknown visibility %q", admin.ErrInvalid, t.Visibility)
+	}
+	if t.Sealed && t.Deadline == nil {
+		return fmt.Errorf("%w: sealed tender requires a deadline", admin.ErrInvalid)
+	}
+
+	var existing models.Tender
+	err = l.tx.Where("organization_id = ? AND name = ?", org.ID, t.Name).First(&existing).Error
+	if err == nil {
+		l.tenders[tenderKey(org.Name, t.Name)] = existing
+		return fmt.Errorf("%w: tender %q", admin.ErrExists, t.Name)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	tender := models.Tender{
+		ID:             fixtureID("tender", org.Name, t.Name),
+		Name:           t.Name,
+		Description:    t.Description,
+		ServiceType:    t.ServiceType,
+		Status:         t.Status,
+		OrganizationID: org.ID,
+		Sealed:         t.Sealed,
+		Deadline:       t.Deadline,
+		AuctionMode:    t.AuctionMode,
+		Visibility:     t.Visibility,
+		Version:        1,
+	}
+	if err := l.tx.Omit("Lots").Create(&tender).Error; err != nil {
+		return err
+	}
+
+	l.tenders[tenderKey(org.Name, t.Name)] = tender
+	return nil
+}
+
+func (l loader) bid(ctx context.Context, b Bid) error {
+	tender, ok := l.tenders[tenderKey(b.TenderOrganization, b.Tender)]
+	if !ok {
+		return fmt.Errorf("%w: tender %q of %q is not defined in fixtures", admin.ErrNotFound, b.Tender, b.TenderOrganization)
+	}
+
+	author, err := l.svc.FindEmployee(ctx, b.Author)
+	if err != nil {
+		return err
+	}
+
+	if b.Name == "" || len(b.Name) > 100 || b.Description == "" || len(b.Description) > 500 {
+		return fmt.Errorf("%w: name must be 1-100 and description 1-500 characters", admin.ErrInvalid)
+	}
+	if b.AuthorType == "" {
+		b.AuthorType = models.AuthorUser
+	}
+	var organizationID *uuid.UUID
+	switch b.AuthorType {
+	case models.AuthorUser:
+	case models.AuthorOrganization:
+		// Предложение от организации подаёт её ответственное лицо, как и через API
+		query := l.tx.Where("user_id = ?", author.ID)
+		if b.Organization != "" {
+			org, err := l.svc.FindOrganization(ctx, b.Organization)
+			if err != nil {
+				return err
+			}
+			query = query.Where("organization_id = ?", org.ID)
+		}
+
+		var resps []models.OrganizationResponsible
+		if err := query.Limit(2).Find(&resps).Error; err != nil {
+			return err
+		}
+		if len(resps) == 0 {
+			return fmt.Errorf("%w: %q is not responsible for organization %q", admin.ErrInvalid, b.Author, b.Organization)
+		}
+		if len(resps) > 1 {
+			return fmt.Errorf("%w: %q is responsible for several organizations, set organization", admin.ErrInvalid, b.Author)
+		}
+		organizationID = &resps[0].OrganizationID
+	default:
+		return fmt.Errorf("%w: unknown author type %q", admin.ErrInvalid, b.AuthorType)
+	}
+	if b.Status == "" {
+		b.Status = models.BidCreated
+	}
+	switch b.Status {
+	case models.BidCreated, models.BidPublished, models.BidCanceled:
+	default:
+		return fmt.Errorf("%w: unknown status %q", admin.ErrInvalid, b.Status)
+	}
+
+	key := tenderKey(b.TenderOrganization, b.Tender) + "/" + b.Name
+
+	var existing models.Bid
+	err = l.tx.Where("tender_id = ? AND name = ?", tender.ID, b.Name).First(&existing).Error
+	if err == nil {
+		l.bids[key] = existing
+		return fmt.Errorf("%w: bid %q", admin.ErrExists, b.Name)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	bid := models.Bid{
+		ID:             fixtureID("bid", tender.ID.String(), b.Name),
+		Name:           b.Name,
+		Description:    b.Description,
+		Status:         b.Status,
+		TenderID:       tender.ID,
+		AuthorType:     b.AuthorType,
+		AuthorID:       author.ID,
+		OrganizationID: organizationID,
+		Price:          b.Price,
+		Version:        1,
+	}
+	if err := l.tx.Omit("Lots").Create(&bid).Error; err != nil {
+		return err
+	}
+
+	l.bids[key] = bid
+	return nil
+}
+
+func (l loader) decision(ctx context.Context, d Decision) error {
+	key := tenderKey(d.TenderOrganization, d.Tender)
+	bid, ok := l.bids[key+"/"+d.Bid]
+	if !ok {
+		return fmt.Errorf("%w: bid %q on tender %q of %q is not defined in fixtures", admin.ErrNotFound, d.Bid, d.Tender, d.TenderOrganization)
+	}
+	tender := l.tenders[key]
+
+	author, err := l.svc.FindEmployee(ctx, d.Author)
+	if err != nil {
+		return err
+	}
+
+	// Существующее решение проверяется первым: при повторной загрузке предложение уже может быть отменено,
+	// а тендер - закрыт этим решением
+	var count int64
+	if err := l.tx.Model(&models.Decision{}).Where("bid_id = ? AND author_id = ? AND lot_id IS NULL", bid.ID, author.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: decision", admin.ErrExists)
+	}
+
+	if d.Decision != models.Approved && d.Decision != models.Rejected {
+		return fmt.Errorf("%w: decision must be Approved or Rejected", admin.ErrInvalid)
+	}
+	if tender.Status != models.Published {
+		return fmt.Errorf("%w: decisions can only be made on published tenders", admin.ErrInvalid)
+	}
+	if bid.Status != models.BidPublished {
+		return fmt.Errorf("%w: decisions can only be made on published bids", admin.ErrInvalid)
+	}
+
+	// Решение принимает ответственное лицо организации тендера, как и через API
+	if err := l.tx.Model(&models.OrganizationResponsible{}).Where("organization_id = ? AND user_id = ?", tender.OrganizationID, author.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %q is not responsible for the tender organization", admin.ErrInvalid, d.Author)
+	}
+
+	// Отклонение отменяет предложение, а кворум одобрений закрывает тендер - так же, как через API
+	_, bid, err = workflow.SubmitDecision(l.tx, nil, l.quorum, &tender, bid, models.Decision{
+		ID:           fixtureID("decision", bid.ID.String(), author.ID.String()),
+		BidID:        bid.ID,
+		AuthorID:     author.ID,
+		DecisionType: d.Decision,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.bids[key+"/"+d.Bid] = bid
+	l.tenders[key] = tender
+	return nil
+}
+
+// tenderKey - естественный ключ тендера: имя организации и имя тендера
+func tenderKey(organization, name string) string {
+	return organization + "/" + name
+}
+
+func fixtureID(kind string, key ...string) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(kind+":"+strings.Join(key, "/")))
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"myapp/config"
+	"myapp/database"
+	"myapp/documents"
+	"myapp/events"
+	"myapp/grpcapi"
+	"myapp/health"
+	"myapp/metrics"
+	"myapp/notify"
+	"myapp/router"
+	"myapp/scheduler"
+	"myapp/storage"
+	"myapp/tracing"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// runServe применяет миграции и запускает HTTP- и gRPC-серверы с фоновыми задачами до сигнала остановки
+func runServe(cfg *config.Config, _ []string) error {
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	gin.SetMode(cfg.HTTP.GinMode)
+
+	// Слушатель событий для потоков Server-Sent Events
+	broker := events.NewBroker(db, cfg.DB.Conn)
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	defer stopBroker()
+	go broker.Run(brokerCtx)
+
+	// Уведомления по электронной почте. Без настроенного SMTP сообщения только пишутся в журнал
+	notifications := notify.NewService(newNotifier(cfg), 1000)
+	notifications.Start(4)
+
+	// Хранилище вложений
+	var store storage.BlobStore
+	switch cfg.Storage.Backend {
+	case "local":
+		store, err = storage.NewLocalStore(cfg.Storage.LocalDir)
+	case "s3":
+		store, err = storage.NewS3Store(context.Background(), cfg.Storage.S3.Endpoint, cfg.Storage.S3.AccessKey, cfg.Storage.S3.SecretKey, cfg.Storage.S3.Bucket, cfg.Storage.S3.UseSSL)
+	default:
+		err = errors.New("unknown blob store " + cfg.Storage.Backend)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize blob store: %w", err)
+	}
+
+	// Печатная форма договоров. Без шрифта сервер работает, но PDF договоров недоступен
+	contracts, err := documents.NewContractRenderer(cfg.Contracts.FontPath)
+	if err != nil {
+		slog.Warn("Contract PDF rendering is disabled", "error", err)
+	}
+
+	// Фоновые задачи
+	jobs := scheduler.New()
+	jobs.Add("open_sealed_tenders", cfg.Scheduler.OpenSealedTenders, scheduler.OpenSealedTenders(db))
+	jobs.Add("close_auction_rounds", cfg.Scheduler.CloseAuctionRounds, scheduler.CloseAuctionRounds(db))
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.Start(jobsCtx)
+
+	// Метрики Prometheus публикуются на отдельном адресе, недоступном из публичной сети
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled {
+		if err := metrics.Register(db); err != nil {
+			return fmt.Errorf("failed to register metrics: %w", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler(cfg.Auth.MetricsToken))
+		metricsSrv = &http.Server{Addr: cfg.Metrics.Address, Handler: mux}
+
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal("Failed to serve metrics", err)
+			}
+		}()
+
+		slog.Info("Serving metrics", "address", cfg.Metrics.Address, "path", "/metrics")
+	}
+
+	// Трассировка запросов к API и базе данных
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, db)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
+	// Проверки готовности: база данных, применённые миграции и фоновые обработчики
+	checker := health.NewChecker(readinessTimeout)
+	checker.Add("database", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	migrations, err := database.SchemaCheck(db)
+	if err != nil {
+		return fmt.Errorf("failed to prepare migration check: %w", err)
+	}
+	checker.Add("migrations", migrations)
+	checker.Add("scheduler", jobs.Check)
+	checker.Add("events", broker.Check)
+	checker.Add("notifications", notifications.Check)
+
+	r := router.SetupRouter(cfg, db, broker, notifications, store, contracts, checker)
+
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Address,
+		Handler:           r,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	// Открытые потоки событий не завершаются сами, поэтому при остановке сервера закрываются подписки
+	srv.RegisterOnShutdown(stopBroker)
+
+	go func() {
+		var err error
+		if cfg.HTTP.TLS.Enabled() {
+			err = srv.ListenAndServeTLS(cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to serve HTTP", err)
+		}
+	}()
+
+	slog.Info("Starting server", "address", cfg.HTTP.Address, "mode", gin.Mode())
+
+	// gRPC API обслуживается теми же маршрутами, что и REST API
+	var grpcSrv *grpc.Server
+	if cfg.GRPC.Enabled {
+		var opts []grpc.ServerOption
+		if cfg.HTTP.TLS.Enabled() {
+			creds, err := credentials.NewServerTLSFromFile(cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load gRPC TLS credentials: %w", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+
+		listener, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			return fmt.Errorf("failed to listen for gRPC: %w", err)
+		}
+
+		grpcSrv = grpcapi.NewServer(r, db, checker, cfg.GRPC.Reflection, opts...)
+		go func() {
+			if err := grpcSrv.Serve(listener); err != nil {
+				fatal("Failed to serve gRPC", err)
+			}
+		}()
+
+		slog.Info("Starting gRPC server", "address", cfg.GRPC.Address, "reflection", cfg.GRPC.Reflection)
+	}
+
+	// Ожидание сигнала для остановки сервера
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	slog.Info("Shutting down server")
+
+	// Проверка готовности начинает отвечать отказом, и балансировщик успевает вывести экземпляр
+	// из ротации до того, как сервер перестанет принимать соединения
+	checker.Drain()
+	time.Sleep(cfg.HTTP.ShutdownDrainDelay)
+
+	// Контекст с таймаутом для завершения текущих запросов
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	// gRPC-сервер завершает текущие вызовы параллельно с HTTP-сервером и в пределах того же таймаута
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		if grpcSrv != nil {
+			stopGRPC(ctx, grpcSrv)
+		}
+	}()
+
+	err = srv.Shutdown(ctx)
+	<-grpcStopped
+	if err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			slog.Warn("Metrics server forced to shutdown", "error", err)
+		}
+	}
+
+	stopJobs()
+	jobs.Wait()
+
+	// Отправка писем, оставшихся в очереди; записи во входящих к этому моменту уже сохранены
+	if err := notifications.Stop(ctx); err != nil {
+		slog.Warn("Some notification emails were not sent", "error", err)
+	}
+
+	// Выгрузка спанов, ещё не отправленных экспортёром
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Failed to flush traces", "error", err)
+	}
+
+	slog.Info("Server exiting")
+
+	return nil
+}
+
+// stopGRPC ожидает завершения текущих вызовов gRPC и обрывает оставшиеся, когда истекает ctx
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// newNotifier возвращает отправку писем через SMTP или, если SMTP не настроен, запись уведомлений в журнал
+func newNotifier(cfg *config.Config) notify.Notifier {
+	if cfg.SMTP.Host == "" {
+		return notify.LogNotifier{}
+	}
+
+	return notify.SMTPNotifier{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	}
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"errors"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// Строковые и числовые литералы в тексте запроса заменяются на "?", чтобы в трассировки не попадали данные.
+// Значения параметров GORM подставляет отдельно от текста запроса, поэтому они в спан не попадают вовсе
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`(^|[^\w$])\d+(?:\.\d+)?\b`)
+)
+
+// gormPlugin создаёт клиентский спан на каждый запрос к базе данных в контексте, переданном через WithContext
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", before("create")),
+		cb.Create().After("*").Register("tracing:after_create", after),
+		cb.Query().Before("*").Register("tracing:before_query", before("query")),
+		cb.Query().After("*").Register("tracing:after_query", after),
+		cb.Update().Before("*").Register("tracing:before_update", before("update")),
+		cb.Update().After("*").Register("tracing:after_update", after),
+		cb.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", after),
+		cb.Row().Before("*").Register("tracing:before_row", before("row")),
+		cb.Row().After("*").Register("tracing:after_row", after),
+		cb.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", after),
+	)
+}
+
+func before(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		name := "db." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+
+		_, span := tracer.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(sanitize(db.Statement.SQL.String())),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+
+	// Отсутствие записи - штатный результат поиска, а не ошибка базы данных
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
+
+func sanitize(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	return numericLiteral.ReplaceAllString(query, "${1}?")
+}
@@ -0,0 +1,246 @@
+package workflow
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"myapp/config"
+	"myapp/events"
+	"myapp/metrics"
+	"myapp/models"
+	"myapp/notify"
+)
+
+// Error - сбой шага перехода. Reason описывает шаг так, как о нём сообщает клиентам API
+type Error struct {
+	Reason string
+	Err    error
+}
+
+func (e *Error) Error() string { return e.Reason + ": " + e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+func fail(reason string, err error) error {
+	return &Error{Reason: reason, Err: err}
+}
+
+// ApprovalQuorum возвращает количество одобрений, необходимое для победы предложения
+func ApprovalQuorum(db *gorm.DB, tender models.Tender, quorum config.Quorum) (int64, error) {
+	var responsibleCount int64
+	if err := db.Model(&models.OrganizationResponsible{}).Where("organization_id = ?", tender.OrganizationID).Count(&responsibleCount).Error; err != nil {
+		return 0, err
+	}
+
+	return min(int64(quorum.MaxApprovals), responsibleCount), nil
+}
+
+// ErrBidNotPublished возвращается, если предложение отозвали или отменили, пока принималось решение
+var ErrBidNotPublished = errors.New("bid is not published")
+
+// ErrLotAwarded возвращается, если лот присуждён другому предложению, пока по этому набирался кворум
+var ErrLotAwarded = errors.New("lot is already awarded")
+
+// SubmitDecision сохраняет решение ответственного лица по опубликованному предложению и выполняет его последствия.
+// Решение без лота: отклонение отменяет предложение, а кворум одобрений закрывает тендер.
+// Решение по лоту (decision.LotID): отклонение снимает предложение только с этого лота, а кворум одобрений
+// присуждает лот предложению и отклоняет по нему остальные. Предложение, отклонённое по всем лотам, отменяется.
+// Тендер закрывается, когда по всем лотам решение принято: лот присуждён или рассматривать по нему больше нечего.
+// Решение и его последствия сохраняются в одной транзакции: если кворум одновременно набрало другое предложение,
+// решение отменяется с ErrNotPublished (тендер уже закрыт) или ErrLotAwarded (лот уже присуждён),
+// а если предложение тем временем отозвали - с ErrBidNotPublished.
+// Проверки прав и допустимости решения выполняет вызывающий код. Возвращает сохранённое решение
+// и перечитанное предложение; tender обновляется, если тендер был закрыт
+func SubmitDecision(db *gorm.DB, notifications *notify.Service, quorum config.Quorum, tender *models.Tender, bid models.Bid, decision models.Decision) (models.Decision, models.Bid, error) {
+	updated := *tender
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		decision, bid, err = submitDecision(tx, notifications, quorum, &updated, bid, decision)
+		return err
+	})
+	if err != nil {
+		return decision, bid, err
+	}
+
+	*tender = updated
+	metrics.Decisions.WithLabelValues(string(decision.DecisionType)).Inc()
+	return decision, bid, nil
+}
+
+func submitDecision(db *gorm.DB, notifications *notify.Service, quorum config.Quorum, tender *models.Tender, bid models.Bid, decision models.Decision) (models.Decision, models.Bid, error) {
+	// Блокировка предложения упорядочивает решение с одновременным отзывом предложения
+	var locked models.Bid
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", bid.ID).First(&locked).Error; err != nil {
+		return decision, bid, fail("Failed to retrieve bid", err)
+	}
+	if locked.Status != models.BidPublished {
+		return decision, bid, ErrBidNotPublished
+	}
+
+	if err := db.Create(&decision).Error; err != nil {
+		return decision, bid, fail("Failed to submit decision", err)
+	}
+
+	var err error
+	if decision.LotID != nil {
+		err = applyLotDecision(db, notifications, quorum, tender, bid, decision)
+	} else {
+		err = applyDecision(db, notifications, quorum, tender, bid, decision)
+	}
+	if err != nil {
+		return decision, bid, err
+	}
+
+	// Повторная загрузка предложения для получения актуальной версии после срабатывания триггера
+	query := db
+	if decision.LotID != nil {
+		query = query.Preload("Lots")
+	}
+	if err := query.Where("id = ?", bid.ID).First(&bid).Error; err != nil {
+		return decision, bid, fail("Failed to reload bid", err)
+	}
+
+	ctx := db.Statement.Context
+	if err := events.DecisionSubmitted(db, decision, bid, *tender); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish decision event", "bid_id", bid.ID, "error", err)
+	}
+	if notifications != nil {
+		if err := notifications.BidDecision(db, bid, *tender, decision); err != nil {
+			slog.ErrorContext(ctx, "Failed to save notifications", "bid_id", bid.ID, "error", err)
+		}
+	}
+
+	return decision, bid, nil
+}
+
+func applyDecision(db *gorm.DB, notifications *notify.Service, quorum config.Quorum, tender *models.Tender, bid models.Bid, decision models.Decision) error {
+	// Обновление статуса предложения, если решение отклонено
+	if decision.DecisionType == models.Rejected {
+		if err := db.Model(&bid).Update("status", models.BidCanceled).Error; err != nil {
+			return fail("Failed to update bid status", err)
+		}
+	}
+
+	// Проверка кворума для одобренных решений
+	var approvedCount int64
+	if err := db.Model(&models.Decision{}).Where("bid_id = ? AND decision_type = ?", bid.ID, models.Approved).Count(&approvedCount).Error; err != nil {
+		return fail("Failed to retrieve decisions", err)
+	}
+
+	required, err := ApprovalQuorum(db, *tender, quorum)
+	if err != nil {
+		return fail("Failed to retrieve responsibles", err)
+	}
+
+	if approvedCount < required {
+		return nil
+	}
+
+	// Условие на статус в закрытии не даёт двум предложениям, одновременно набравшим кворум, обоим победить
+	if err := CloseOnQuorum(db, notifications, tender); err != nil {
+		if errors.Is(err, ErrNotPublished) {
+			return err
+		}
+		return fail("Failed to update tender status", err)
+	}
+	return nil
+}
+
+func applyLotDecision(db *gorm.DB, notifications *notify.Service, quorum config.Quorum, tender *models.Tender, bid models.Bid, decision models.Decision) error {
+	lotID := *decision.LotID
+
+	if decision.DecisionType == models.Rejected {
+		if err := db.Model(&models.BidLot{}).Where("bid_id = ? AND lot_id = ?", bid.ID, lotID).Update("status", models.BidLotRejected).Error; err != nil {
+			return fail("Failed to update bid lot status", err)
+		}
+		if err := cancelRejectedBids(db, []uuid.UUID{bid.ID}); err != nil {
+			return err
+		}
+
+		// Отклонение последнего рассматриваемого предложения завершает лот без победителя
+		return closeIfResolved(db, notifications, tender, CloseTender)
+	}
+
+	// Проверка кворума для одобренных решений по лоту
+	var approvedCount int64
+	if err := db.Model(&models.Decision{}).Where("bid_id = ? AND lot_id = ? AND decision_type = ?", bid.ID, lotID, models.Approved).Count(&approvedCount).Error; err != nil {
+		return fail("Failed to retrieve decisions", err)
+	}
+
+	required, err := ApprovalQuorum(db, *tender, quorum)
+	if err != nil {
+		return fail("Failed to retrieve responsibles", err)
+	}
+
+	if approvedCount < required {
+		return nil
+	}
+
+	// Условие на статус не даёт двум предложениям, одновременно набравшим кворум, получить один лот
+	result := db.Model(&models.TenderLot{}).Where("id = ? AND status = ?", lotID, models.LotOpen).
+		Updates(map[string]any{"status": models.LotAwarded, "winner_bid_id": bid.ID})
+	if result.Error != nil {
+		return fail("Failed to award lot", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrLotAwarded
+	}
+	if err := db.Model(&models.BidLot{}).Where("bid_id = ? AND lot_id = ?", bid.ID, lotID).Update("status", models.BidLotApproved).Error; err != nil {
+		return fail("Failed to update bid lot status", err)
+	}
+
+	// Остальные предложения на присуждённый лот его уже не получат
+	var competitors []uuid.UUID
+	if err := db.Model(&models.BidLot{}).Where("lot_id = ? AND status = ?", lotID, models.BidLotPending).Pluck("bid_id", &competitors).Error; err != nil {
+		return fail("Failed to retrieve lots", err)
+	}
+	if len(competitors) > 0 {
+		if err := db.Model(&models.BidLot{}).Where("lot_id = ? AND bid_id IN ?", lotID, competitors).Update("status", models.BidLotRejected).Error; err != nil {
+			return fail("Failed to update bid lot status", err)
+		}
+		if err := cancelRejectedBids(db, competitors); err != nil {
+			return err
+		}
+	}
+
+	return closeIfResolved(db, notifications, tender, CloseOnQuorum)
+}
+
+// cancelRejectedBids отменяет опубликованные предложения из bidIDs, отклонённые по всем своим лотам
+func cancelRejectedBids(db *gorm.DB, bidIDs []uuid.UUID) error {
+	err := db.Model(&models.Bid{}).
+		Where("id IN ? AND status = ?", bidIDs, models.BidPublished).
+		Where("NOT EXISTS (SELECT 1 FROM bid_lots WHERE bid_lots.bid_id = bids.id AND bid_lots.status <> ?)", models.BidLotRejected).
+		Update("status", models.BidCanceled).Error
+	if err != nil {
+		return fail("Failed to update bid status", err)
+	}
+	return nil
+}
+
+// closeIfResolved закрывает тендер переходом close, когда по всем лотам решение принято: лот присуждён
+// или на него не осталось рассматриваемых предложений. Тендер, уже закрытый параллельно, не считается ошибкой:
+// решение принято в любом случае
+func closeIfResolved(db *gorm.DB, notifications *notify.Service, tender *models.Tender, close func(*gorm.DB, *notify.Service, *models.Tender) error) error {
+	var unresolved int64
+	err := db.Model(&models.TenderLot{}).
+		Where("tender_id = ? AND status = ?", tender.ID, models.LotOpen).
+		Where(`EXISTS (
+            SELECT 1 FROM bid_lots JOIN bids ON bids.id = bid_lots.bid_id
+            WHERE bid_lots.lot_id = tender_lots.id AND bid_lots.status = ? AND bids.status = ?
+        )`, models.BidLotPending, models.BidPublished).
+		Count(&unresolved).Error
+	if err != nil {
+		return fail("Failed to retrieve lots", err)
+	}
+	if unresolved > 0 {
+		return nil
+	}
+
+	if err := close(db, notifications, tender); err != nil && !errors.Is(err, ErrNotPublished) {
+		return fail("Failed to update tender status", err)
+	}
+	return nil
+}
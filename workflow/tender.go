@@ -0,0 +1,61 @@
+// Package workflow содержит переходы тендеров и предложений, общие для REST API, gRPC, планировщика и
+// команд администрирования. Переходы не зависят от транспорта: ошибки возвращаются вызывающему коду,
+// а сбои побочных действий (событий и уведомлений) только записываются в журнал
+package workflow
+
+import (
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"myapp/events"
+	"myapp/metrics"
+	"myapp/models"
+	"myapp/notify"
+)
+
+// ErrNotPublished возвращается при попытке закрыть тендер, который не опубликован:
+// созданный тендер ещё никто не видел, а закрытый уже закрыт
+var ErrNotPublished = errors.New("tender is not published")
+
+// CloseTender закрывает опубликованный тендер и перечитывает его, чтобы получить версию,
+// увеличенную триггером. После закрытия публикует событие и сохраняет уведомления участникам;
+// notifications может быть nil
+func CloseTender(db *gorm.DB, notifications *notify.Service, tender *models.Tender) error {
+	// Условие на статус не даёт закрыть тендер дважды при одновременном закрытии из разных мест
+	result := db.Model(tender).Where("status = ?", models.Published).Update("status", models.Closed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotPublished
+	}
+
+	if err := db.Where("id = ?", tender.ID).First(tender).Error; err != nil {
+		return err
+	}
+
+	metrics.TenderStatusChanges.WithLabelValues(string(tender.Status)).Inc()
+
+	ctx := db.Statement.Context
+	if err := events.TenderStatusChanged(db, *tender); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish tender status event", "tender_id", tender.ID, "error", err)
+	}
+	if notifications != nil {
+		if err := notifications.TenderClosed(db, *tender); err != nil {
+			slog.ErrorContext(ctx, "Failed to save notifications", "tender_id", tender.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// CloseOnQuorum закрывает тендер, по которому набран кворум одобрений, и учитывает это в метриках
+func CloseOnQuorum(db *gorm.DB, notifications *notify.Service, tender *models.Tender) error {
+	if err := CloseTender(db, notifications, tender); err != nil {
+		return err
+	}
+
+	metrics.QuorumClosures.Inc()
+	return nil
+}